@@ -20,6 +20,9 @@ type Algorithm interface {
 type AlgorithmStats struct {
 	// Calls is how many times the EvaluateChanges() was called.
 	Calls uint
+	// Hits is how many times the algorithm found a pattern which resulted in
+	// changes. Not all algorithms track this.
+	Hits uint
 	// Changes is how many tiles were changed by the algorithm.
 	Changes uint
 	// Duration is the time spent within EvaluateChanges().
@@ -291,10 +294,102 @@ func (a algoOnlyRow) EvaluateChanges(b *Board, changes []uint8) bool {
 	return true
 }
 
-// algoNakedSubset finds any tiles within a neighbor set for which the number of
-// possible values within the tile is the same as the number of tiles with the
-// same possible values, and eliminates the values in those tiles from all other
-// tiles within the set.
+// algoOnlyRegion checks if there is only a single region within a row or column
+// which can hold a value. If so, it eliminates the value from the possibilities
+// within the rest of the region. This is the reverse of algoOnlyRow, and is also
+// known as box-line reduction, or claiming.
+type algoOnlyRegion struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoOnlyRegion) Name() string { return "algoOnlyRegion" }
+
+func (a *algoOnlyRegion) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoOnlyRegion) EvaluateChanges(b *Board, changes []uint8) bool {
+	var rowsSeen uint16
+	var columnsSeen uint16
+	for _, ti := range changes {
+		x, y := indexToXY(ti)
+
+		rowMask := uint16(1 << y)
+		if rowsSeen&rowMask == 0 {
+			rowsSeen |= rowMask
+			if !a.evaluateLine(b, RowIndices[y][:]) {
+				return false
+			}
+		}
+
+		columnMask := uint16(1 << x)
+		if columnsSeen&columnMask == 0 {
+			columnsSeen |= columnMask
+			if !a.evaluateLine(b, ColumnIndices[x][:]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// evaluateLine evaluates the algorithm for the given row or column.
+func (a *algoOnlyRegion) evaluateLine(b *Board, lineIndices []uint8) bool {
+OnePossibleRegionLoop:
+	for v := Tile(1); v < tAny; v = v << 1 {
+		tcRgn := uint8(255)
+		for _, nti := range lineIndices {
+			nt := b.Tiles[nti]
+			if nt == v {
+				// this value has already been found
+				continue OnePossibleRegionLoop
+			}
+			if nt&v == 0 {
+				// not a possible tile
+				continue
+			}
+			rgnIdx := tileIndexToRegionIndex(nti)
+			if tcRgn == rgnIdx {
+				// region already a candidate
+				continue
+			}
+			if tcRgn != 255 {
+				// multiple candidate regions
+				continue OnePossibleRegionLoop
+			}
+			tcRgn = rgnIdx
+		}
+		if tcRgn == 255 {
+			// no candidate regions. Wat?
+			return false
+		}
+
+		// iterate over the candidate region, excluding the value from tiles outside
+		// the line
+		changed := false
+		for _, nti := range RegionIndices[tcRgn][:] {
+			if containsIndex(lineIndices, nti) {
+				// skip our line
+				continue
+			}
+			c, ok := b.unset(nti, v)
+			if !ok {
+				// invalid board configuration
+				return false
+			}
+			changed = changed || c
+		}
+		if changed {
+			a.AlgoStats.Hits++
+		}
+	}
+
+	return true
+}
+
+// algoNakedSubset finds any N tiles (2-4) within a neighbor set which between
+// them have only N possible values, and eliminates those values from all other
+// tiles within the set. The tiles don't need to have the same possible values.
+// E.G. [1,2],[2,3],[1,3] is a naked triple.
 //
 // https://www.kristanix.com/sudokuepic/sudoku-solving-techniques.php "Naked Subset"
 //
@@ -351,31 +446,47 @@ func (a algoNakedSubset) EvaluateChanges(b *Board, changes []uint8) bool {
 
 // evaluateChangesNS evaluates the algorithm for the given neighbor set.
 func (a algoNakedSubset) evaluateChangesNS(b *Board, idcs []uint8) bool {
-	setCounts := map[Tile]uint8{}
-	for _, nti := range idcs {
-		nt := b.Tiles[nti]
-		if nt.isKnown() {
-			// Technically this is one such case. One possible value within the tile,
-			// and one tile with this possible set. But this is already handled by
-			// algoOnePossibleTile.
+	// unknowns is a bit mask of the positions within the set of the tiles which
+	// don't have a known value.
+	var unknowns uint16
+	for i, nti := range idcs {
+		if !b.Tiles[nti].isKnown() {
+			unknowns |= 1 << uint16(i)
+		}
+	}
+	unknownsCount := len(MaskBits[unknowns])
+
+	for subset := unknowns; subset != 0; subset = (subset - 1) & unknowns {
+		size := len(MaskBits[subset])
+		if size < 2 || size > 4 || size == unknownsCount {
+			// A single tile is already handled by algoKnownValueElimination. And
+			// if the subset is all the unknown tiles, there's nothing to eliminate
+			// from.
+			// Larger subsets don't need to be checked, since the remaining tiles
+			// would then form a hidden subset of size 4 or less.
 			continue
 		}
 
-		setCounts[nt]++
-	}
-	for t, setCount := range setCounts {
-		possibilityCount := uint8(len(MaskBits[t]))
-		if possibilityCount != setCount {
+		var t Tile
+		for _, i := range MaskBits[subset] {
+			t |= b.Tiles[idcs[i]]
+		}
+		possibilityCount := len(MaskBits[t])
+		if possibilityCount < size {
+			// more tiles than possible values
+			return false
+		}
+		if possibilityCount != size {
 			continue
 		}
+
 		// if we're here, then we have a combination of N tiles with N possibilities.
-		for _, nti := range idcs {
-			nt := b.Tiles[nti]
-			if nt == t {
+		for i, nti := range idcs {
+			if subset&(1<<uint16(i)) != 0 {
 				// this is one of the N tiles
 				continue
 			}
-			if nt&t != 0 {
+			if b.Tiles[nti]&t != 0 {
 				// this tile has some of the possibilities, remove them
 				if !b.set(nti, ^t) {
 					return false
@@ -406,9 +517,9 @@ func (a algoHiddenSubset) EvaluateChanges(b *Board, changes []uint8) bool {
 	// the algorithm works like this:
 	// 1. Iterate over the values 1-9
 	// 1.1. Find each tile which can hold that value.
-	// 2. Group the values together which have the same candidate tiles.
-	// 2.1 If the number of grouped values is the same as the number of candidate
-	//     tiles, that is a hidden subset.
+	// 2. Take each combination of 2-4 values, and combine their candidate tiles.
+	// 2.1 If the number of values is the same as the number of candidate tiles,
+	//     that is a hidden subset.
 	// 2.2 Remove all other possible values from the candidate tiles.
 
 	var regionsSeen uint16
@@ -457,37 +568,55 @@ func (a algoHiddenSubset) evaluateChangesNS(b *Board, idcs []uint8) bool {
 	// valueTileIndices is a list of values to a bit mask of tile indices which hold that value.
 	// E.G. `3 => 0b001000010` means that the value 3 is a possibility for tiles 2 & 7.
 	valueTileIndices := [9]uint16{}
+	// unknownValues is a bit mask of the values which aren't yet known within the
+	// set.
+	var unknownValues Tile
 	// 1. Iterate over the values 1-9
+ValueLoop:
 	for v := uint8(0); v < 9; v++ { // v is one less than the actual number we're dealing with
 		// 1.1. Find each tile which can hold that value.
 		for i, nti := range idcs {
 			nt := b.Tiles[nti]
+			if nt == 1<<v {
+				// this value has already been found
+				valueTileIndices[v] = 0
+				continue ValueLoop
+			}
 			if nt&(1<<v) == 0 {
 				continue
 			}
 			valueTileIndices[v] |= 1 << uint16(i)
 		}
+		unknownValues |= 1 << v
 	}
+	unknownsCount := len(MaskBits[unknownValues])
+
+	// 2. Take each combination of 2-4 values, and combine their candidate tiles.
+	for valuesMask := unknownValues; valuesMask != 0; valuesMask = (valuesMask - 1) & unknownValues {
+		values := MaskBits[valuesMask]
+		if len(values) < 2 || len(values) > 4 || len(values) == unknownsCount {
+			// A single value is already handled by algoOnePossibleTile. And if the
+			// combination is all the unknown values, there's nothing to eliminate.
+			// Larger combinations don't need to be checked, since the remaining
+			// tiles would then form a naked subset of size 4 or less.
+			continue
+		}
 
-	// 2. Group the values together which have the same candidate tiles.
-	// We basically reverse the valueTileIndices list.
-	// sets is a map of a set of indices (as a bit mask) to a bit mask of the
-	// values in that set.
-	// E.G. `0b001000010 => 0b000000101` means that tiles 2 & 7 are both the only
-	// candidates for values 1 & 3.
-	sets := make(map[uint16]Tile, 9)
-	for v, stiMask := range valueTileIndices[:] {
-		sets[stiMask] |= 1 << uint8(v)
-	}
-
-	// 2.1 If the number of grouped values is the same as the number of candidate
-	// tiles, that is a hidden subset.
-	for stiMask, valuesMask := range sets {
+		// stiMask is the bit mask of the candidate tiles.
+		var stiMask uint16
+		for _, v := range values {
+			stiMask |= valueTileIndices[v]
+		}
 		// break the tile indicies bitmask out into separate indicies
 		tileIndices := MaskBits[stiMask]
 
-		valuesCount := len(MaskBits[valuesMask])
-		if valuesCount != len(tileIndices) {
+		// 2.1 If the number of values is the same as the number of candidate
+		// tiles, that is a hidden subset.
+		if len(tileIndices) < len(values) {
+			// more values than tiles to hold them
+			return false
+		}
+		if len(tileIndices) != len(values) {
 			// not a hidden subset
 			continue
 		}
@@ -502,3 +631,2460 @@ func (a algoHiddenSubset) evaluateChangesNS(b *Board, idcs []uint8) bool {
 
 	return true
 }
+
+// changedValuesAndLines summarizes a list of changed tiles into the values
+// which might have been removed from those tiles, along with bit masks of the
+// rows & columns which contain the tiles.
+// Since we only know which tiles changed, and not what they used to hold, any
+// value which is not a possibility for a changed tile is considered as possibly
+// having been removed.
+func changedValuesAndLines(b *Board, changes []uint8) (values Tile, rows, columns uint16) {
+	for _, ti := range changes {
+		values |= ^b.Tiles[ti] & tAny
+		x, y := indexToXY(ti)
+		rows |= 1 << y
+		columns |= 1 << x
+	}
+	return
+}
+
+// lineValueMasks builds a bit mask for each of the given lines indicating which
+// tiles within the line can hold the value v.
+// E.G. `masks[2] == 0b000010010` means that v can only be in line 2 at
+// positions 1 & 4.
+// Lines in which the value is already known have a mask of 0.
+func lineValueMasks(b *Board, lines *[9][9]uint8, v Tile) (masks [9]uint16) {
+LineLoop:
+	for li := range lines {
+		for i, ti := range lines[li][:] {
+			t := b.Tiles[ti]
+			if t == v {
+				masks[li] = 0
+				continue LineLoop
+			}
+			if t&v != 0 {
+				masks[li] |= 1 << uint16(i)
+			}
+		}
+	}
+	return
+}
+
+// algoFish finds basic fish patterns (X-Wing, Swordfish, Jellyfish).
+// If there are N rows in which a value can only be in the same N columns, then
+// the value must be in those columns within those rows, and it can be
+// eliminated from the rest of the tiles in those columns. The same applies with
+// rows and columns swapped.
+//
+// https://www.sudokuwiki.org/X_Wing_Strategy
+type algoFish struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoFish) Name() string { return "algoFish" }
+
+func (a *algoFish) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoFish) EvaluateChanges(b *Board, changes []uint8) bool {
+	// A fish can only have appeared if one of its base lines lost a possibility for
+	// the value. So we only look at the values which might have been removed, and
+	// only at fish which have a changed line as one of their base lines.
+	values, rowsChanged, columnsChanged := changedValuesAndLines(b, changes)
+
+	for _, v := range MaskBits[values] {
+		// rows as the base lines, columns as the cover lines
+		if !a.evaluateChangesLines(b, Tile(1<<v), &RowIndices, &ColumnIndices, rowsChanged) {
+			return false
+		}
+		// columns as the base lines, rows as the cover lines
+		if !a.evaluateChangesLines(b, Tile(1<<v), &ColumnIndices, &RowIndices, columnsChanged) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evaluateChangesLines looks for fish of value v using the given base & cover
+// lines. baseChanged is a bit mask of the base lines which have changed.
+func (a *algoFish) evaluateChangesLines(b *Board, v Tile, baseLines, coverLines *[9][9]uint8, baseChanged uint16) bool {
+	masks := lineValueMasks(b, baseLines, v)
+
+	// eligible is a bit mask of the base lines which have few enough possible
+	// tiles to be part of a fish.
+	var eligible uint16
+	for li, m := range masks {
+		if pc := len(MaskBits[m]); pc >= 2 && pc <= 4 {
+			eligible |= 1 << uint16(li)
+		}
+	}
+
+	for size := 2; size <= 4; size++ {
+		for baseMask := uint16(0); baseMask < 512; baseMask++ {
+			if baseMask&^eligible != 0 || baseMask&baseChanged == 0 || len(MaskBits[baseMask]) != size {
+				continue
+			}
+
+			var coverMask uint16
+			for _, li := range MaskBits[baseMask] {
+				coverMask |= masks[li]
+			}
+			coverCount := len(MaskBits[coverMask])
+			if coverCount < size {
+				// N lines which must each hold the value, but fewer than N places to put it.
+				return false
+			}
+			if coverCount > size {
+				// not a fish
+				continue
+			}
+
+			// Found a fish. Eliminate the value from the cover lines outside the base
+			// lines.
+			hit := false
+			for _, ci := range MaskBits[coverMask] {
+				for li, nti := range coverLines[ci][:] {
+					if baseMask&(1<<uint16(li)) != 0 {
+						// one of the base lines
+						continue
+					}
+					changed, ok := b.unset(nti, v)
+					if !ok {
+						// invalid board configuration
+						return false
+					}
+					hit = hit || changed
+				}
+			}
+			if hit {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+
+	return true
+}
+
+// algoFinnedFish finds finned & sashimi fish patterns (up to Jellyfish).
+// These are fish where the base lines have extra possible tiles (the fins)
+// outside of the cover lines, but all the fins are within the same region.
+// Either one of the fins holds the value, or the fish is real. In both cases,
+// tiles which are both in a cover line and in the region of the fins can't hold
+// the value.
+// Sashimi fish are the case where removing the fins would leave a base line
+// with less than 2 possible tiles. These don't need any special handling.
+//
+// https://www.sudokuwiki.org/Finned_X_Wing
+// https://www.sudokuwiki.org/Sashimi_Fish
+type algoFinnedFish struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoFinnedFish) Name() string { return "algoFinnedFish" }
+
+func (a *algoFinnedFish) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoFinnedFish) EvaluateChanges(b *Board, changes []uint8) bool {
+	// Same as with algoFish, a finned fish is defined entirely by its base lines,
+	// so we only need to look at fish which have a changed base line.
+	values, rowsChanged, columnsChanged := changedValuesAndLines(b, changes)
+
+	for _, v := range MaskBits[values] {
+		// rows as the base lines, columns as the cover lines
+		if !a.evaluateChangesLines(b, Tile(1<<v), &RowIndices, &ColumnIndices, rowsChanged) {
+			return false
+		}
+		// columns as the base lines, rows as the cover lines
+		if !a.evaluateChangesLines(b, Tile(1<<v), &ColumnIndices, &RowIndices, columnsChanged) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// evaluateChangesLines looks for finned fish of value v using the given base &
+// cover lines. baseChanged is a bit mask of the base lines which have changed.
+func (a *algoFinnedFish) evaluateChangesLines(b *Board, v Tile, baseLines, coverLines *[9][9]uint8, baseChanged uint16) bool {
+	masks := lineValueMasks(b, baseLines, v)
+
+	var eligible uint16
+	for li, m := range masks {
+		if len(MaskBits[m]) >= 2 {
+			eligible |= 1 << uint16(li)
+		}
+	}
+
+	for size := 2; size <= 4; size++ {
+		for baseMask := uint16(0); baseMask < 512; baseMask++ {
+			if baseMask&^eligible != 0 || baseMask&baseChanged == 0 || len(MaskBits[baseMask]) != size {
+				continue
+			}
+
+			var unionMask uint16
+			for _, li := range MaskBits[baseMask] {
+				unionMask |= masks[li]
+			}
+			if len(MaskBits[unionMask]) <= size {
+				// no room for fins. This is either a basic fish, or an invalid board, both
+				// of which are handled by algoFish.
+				continue
+			}
+
+			// The fins all have to be in the same region, so the cover lines have to
+			// include every line the base touches, except for those within a single
+			// block of 3 lines (the fins' region). So for each block, try each
+			// combination of N cover lines meeting that.
+			for blk := uint16(0); blk < 3; blk++ {
+				finZone := uint16(0b111 << (blk * 3))
+				required := unionMask &^ finZone
+				if len(MaskBits[required]) > size {
+					continue
+				}
+				optional := unionMask & finZone
+				for sub := optional; sub != 0; sub = (sub - 1) & optional {
+					coverMask := required | sub
+					if len(MaskBits[coverMask]) != size || coverMask == unionMask {
+						continue
+					}
+					if !a.evaluateFish(b, v, baseLines, coverLines, masks, baseMask, coverMask) {
+						return false
+					}
+				}
+				if len(MaskBits[required]) == size && required != unionMask {
+					if !a.evaluateFish(b, v, baseLines, coverLines, masks, baseMask, required) {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// evaluateFish evaluates the fish formed by the given base & cover lines.
+func (a *algoFinnedFish) evaluateFish(b *Board, v Tile, baseLines, coverLines *[9][9]uint8, masks [9]uint16, baseMask, coverMask uint16) bool {
+	finRgnIdx, ok := a.finRegion(baseLines, masks, baseMask, coverMask)
+	if !ok {
+		return true
+	}
+
+	// Eliminate the value from the tiles in the cover lines, within the fin
+	// region, which are not in the base lines.
+	hit := false
+	for _, ci := range MaskBits[coverMask] {
+		for li, nti := range coverLines[ci][:] {
+			if baseMask&(1<<uint16(li)) != 0 {
+				// one of the base lines
+				continue
+			}
+			if tileIndexToRegionIndex(nti) != finRgnIdx {
+				continue
+			}
+			changed, ok := b.unset(nti, v)
+			if !ok {
+				// invalid board configuration
+				return false
+			}
+			hit = hit || changed
+		}
+	}
+	if hit {
+		a.AlgoStats.Hits++
+	}
+	return true
+}
+
+// finRegion finds the region which holds all the fins of the fish formed by the
+// given base & cover lines. If the fins are not all in the same region, false is
+// returned.
+func (a algoFinnedFish) finRegion(baseLines *[9][9]uint8, masks [9]uint16, baseMask, coverMask uint16) (uint8, bool) {
+	finRgnIdx := uint8(255)
+	for _, li := range MaskBits[baseMask] {
+		for _, fi := range MaskBits[masks[li]&^coverMask] {
+			rgnIdx := tileIndexToRegionIndex(baseLines[li][fi])
+			if finRgnIdx != 255 && finRgnIdx != rgnIdx {
+				return 0, false
+			}
+			finRgnIdx = rgnIdx
+		}
+	}
+	return finRgnIdx, finRgnIdx != 255
+}
+
+// changedTilesAndPeers returns a lookup table indicating which tiles are either
+// in the list of changed tiles, or are a peer of a changed tile.
+func changedTilesAndPeers(changes []uint8) (seen [9 * 9]bool) {
+	for _, ti := range changes {
+		seen[ti] = true
+		for _, nti := range PeerIndices[ti][:] {
+			seen[nti] = true
+		}
+	}
+	return
+}
+
+// algoXYWing finds XY-Wings.
+// The pattern is a pivot tile with 2 possible values [X,Y], which sees a pincer
+// tile with possible values [X,Z], and another pincer with [Y,Z]. Whichever
+// value the pivot holds, one of the pincers has to be Z. So any tile which sees
+// both pincers can't hold Z.
+//
+// https://www.sudokuwiki.org/Y_Wing_Strategy
+type algoXYWing struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoXYWing) Name() string { return "algoXYWing" }
+
+func (a *algoXYWing) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoXYWing) EvaluateChanges(b *Board, changes []uint8) bool {
+	// A wing can only have appeared if one of its tiles changed. Since the pincers
+	// are peers of the pivot, the pivot must be a changed tile or a peer of one.
+	pivotCandidates := changedTilesAndPeers(changes)
+
+	for pti, pt := range b.Tiles {
+		if !pivotCandidates[pti] || len(MaskBits[pt]) != 2 {
+			continue
+		}
+		peers := PeerIndices[pti][:]
+		for i, ati := range peers {
+			at := b.Tiles[ati]
+			if len(MaskBits[at]) != 2 || len(MaskBits[at&pt]) != 1 {
+				continue
+			}
+			// The other pincer has the pivot value not shared with the first pincer,
+			// plus Z.
+			z := at &^ pt
+			bt := pt&^at | z
+			for _, bti := range peers[i+1:] {
+				if b.Tiles[bti] != bt {
+					continue
+				}
+				changed, ok := eliminateFromCommonPeers(b, z, ati, bti)
+				if !ok {
+					return false
+				}
+				if changed {
+					a.AlgoStats.Hits++
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// algoXYZWing finds XYZ-Wings.
+// This is like an XY-Wing, except the pivot tile has 3 possible values
+// [X,Y,Z]. Since the pivot can also be Z, only tiles which see the pivot as well
+// as both pincers can have Z eliminated.
+//
+// https://www.sudokuwiki.org/XYZ_Wing
+type algoXYZWing struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoXYZWing) Name() string { return "algoXYZWing" }
+
+func (a *algoXYZWing) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoXYZWing) EvaluateChanges(b *Board, changes []uint8) bool {
+	pivotCandidates := changedTilesAndPeers(changes)
+
+	for pti, pt := range b.Tiles {
+		if !pivotCandidates[pti] || len(MaskBits[pt]) != 3 {
+			continue
+		}
+		peers := PeerIndices[pti][:]
+		for i, ati := range peers {
+			at := b.Tiles[ati]
+			if len(MaskBits[at]) != 2 || at&^pt != 0 {
+				continue
+			}
+			for _, bti := range peers[i+1:] {
+				bt := b.Tiles[bti]
+				if len(MaskBits[bt]) != 2 || bt&^pt != 0 || at|bt != pt {
+					continue
+				}
+				changed, ok := eliminateFromCommonPeers(b, at&bt, uint8(pti), ati, bti)
+				if !ok {
+					return false
+				}
+				if changed {
+					a.AlgoStats.Hits++
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// eliminateFromCommonPeers removes the value v from all tiles which see every
+// one of the given tiles.
+// Returns whether any tile was changed, and false for ok if the board is
+// invalid.
+func eliminateFromCommonPeers(b *Board, v Tile, tis ...uint8) (changed, ok bool) {
+PeerLoop:
+	for _, nti := range PeerIndices[tis[0]][:] {
+		if b.Tiles[nti]&v == 0 {
+			continue
+		}
+		for _, ti := range tis[1:] {
+			if !tilesSee(nti, ti) {
+				continue PeerLoop
+			}
+		}
+		c, ok := b.unset(nti, v)
+		if !ok {
+			// invalid board configuration
+			return changed, false
+		}
+		changed = changed || c
+	}
+	return changed, true
+}
+
+// strongLink is a pair of tiles which are the only 2 possible tiles for a value
+// within a neighbor set (a conjugate pair). If one of the tiles does not hold
+// the value, the other one must.
+type strongLink struct {
+	ti1, ti2 uint8
+}
+
+// strongLinks finds all the strong links for the value v across all the
+// neighbor sets. A pair of tiles which is linked within multiple neighbor sets
+// (E.G. a row & a region) is only returned once.
+func strongLinks(b *Board, v Tile) []strongLink {
+	var links []strongLink
+NeighborSetLoop:
+	for _, idcs := range NeighborSetIndices {
+		ti1, ti2 := uint8(255), uint8(255)
+		for _, nti := range idcs {
+			nt := b.Tiles[nti]
+			if nt == v {
+				// this value has already been found
+				continue NeighborSetLoop
+			}
+			if nt&v == 0 {
+				continue
+			}
+			if ti2 != 255 {
+				// more than 2 possible tiles
+				continue NeighborSetLoop
+			}
+			if ti1 == 255 {
+				ti1 = nti
+			} else {
+				ti2 = nti
+			}
+		}
+		if ti2 == 255 {
+			continue
+		}
+		for _, sl := range links {
+			if sl.ti1 == ti1 && sl.ti2 == ti2 {
+				continue NeighborSetLoop
+			}
+		}
+		links = append(links, strongLink{ti1, ti2})
+	}
+	return links
+}
+
+// algoWWing finds W-Wings.
+// The pattern is 2 tiles which don't see each other, both with the same 2
+// possible values [X,Y], and a strong link on X where one end sees the first
+// tile, and the other end sees the second tile. If either tile were X, the
+// strong link would force the other tile to be X too, which would leave the
+// strong link with no X. So one of the 2 tiles has to be Y, and any tile which
+// sees both can't hold Y.
+//
+// https://www.sudokuwiki.org/W_Wing_Strategy
+type algoWWing struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoWWing) Name() string { return "algoWWing" }
+
+func (a *algoWWing) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoWWing) EvaluateChanges(b *Board, changes []uint8) bool {
+	// A W-Wing can only have appeared if one of its 2 tiles has changed, or if its
+	// strong link on X is new, meaning a tile in the link's neighbor set lost X.
+	// As with changedValuesAndLines, any value which is not a possibility for a
+	// changed tile is considered as possibly having been removed.
+	var changed [9 * 9]bool
+	// linkTiles holds, for each value, the tiles within the neighbor sets which
+	// might have a new strong link on the value.
+	var linkTiles [9]tileSet
+	for _, ti := range changes {
+		changed[ti] = true
+		x, y := indexToXY(ti)
+		for _, si := range [3]uint8{y, 9 + x, 18 + tileIndexToRegionIndex(ti)} {
+			for _, v := range MaskBits[^b.Tiles[ti]&tAny] {
+				for _, nti := range NeighborSetIndices[si] {
+					linkTiles[v].add(nti)
+				}
+			}
+		}
+	}
+	allTiles := tileSet{^uint64(0), ^uint64(0)}
+
+	// strong links are only calculated for each value once needed
+	var links [9][]strongLink
+	var linksFound Tile
+
+	for ati := uint8(0); ati < 9*9; ati++ {
+		at := b.Tiles[ati]
+		if len(MaskBits[at]) != 2 {
+			continue
+		}
+		for bti := ati + 1; bti < 9*9; bti++ {
+			if b.Tiles[bti] != at || tilesSee(ati, bti) {
+				continue
+			}
+			for _, x := range MaskBits[at] {
+				within := allTiles
+				if !changed[ati] && !changed[bti] {
+					within = linkTiles[x]
+					if within.empty() {
+						continue
+					}
+				}
+				xt := Tile(1 << x)
+				if linksFound&xt == 0 {
+					links[x] = strongLinks(b, xt)
+					linksFound |= xt
+				}
+				if !a.hasBridge(links[x], ati, bti, within) {
+					continue
+				}
+				changed, ok := eliminateFromCommonPeers(b, at&^xt, ati, bti)
+				if !ok {
+					return false
+				}
+				if changed {
+					a.AlgoStats.Hits++
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// hasBridge indicates whether any of the strong links with both ends within the
+// given tiles has one end which sees tile ati, and the other end which sees tile
+// bti.
+func (a algoWWing) hasBridge(links []strongLink, ati, bti uint8, within tileSet) bool {
+	for _, sl := range links {
+		if !within.has(sl.ti1) || !within.has(sl.ti2) {
+			continue
+		}
+		if tilesSee(sl.ti1, ati) && tilesSee(sl.ti2, bti) ||
+			tilesSee(sl.ti1, bti) && tilesSee(sl.ti2, ati) {
+			return true
+		}
+	}
+	return false
+}
+
+// algoColoring performs simple coloring and multi-coloring on each value.
+// Tiles joined by strong links are grouped into clusters, and each cluster is
+// colored with 2 alternating colors. Within a cluster, all tiles of one color
+// hold the value, and all tiles of the other color do not.
+//
+// Simple coloring then applies 2 rules:
+//   - Color wrap - If 2 tiles of the same color see each other, that color can't
+//     hold the value, so it is removed from all tiles of that color.
+//   - Color trap - A tile outside the cluster which sees tiles of both colors
+//     can't hold the value.
+//
+// Multi-coloring looks at pairs of clusters. If a tile of color A1 (cluster A)
+// sees a tile of color B1 (cluster B), then A1 & B1 can't both be true, so one
+// of A2 or B2 must be.
+// * Any tile which sees both an A2 tile & a B2 tile can't hold the value.
+// * If color A1 sees both B1 & B2, then A1 can't hold the value.
+//
+// https://www.sudokuwiki.org/Singles_Chains
+// https://www.sudokuwiki.org/Multi_Colouring
+type algoColoring struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoColoring) Name() string { return "algoColoring" }
+
+func (a *algoColoring) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoColoring) EvaluateChanges(b *Board, changes []uint8) bool {
+	// Clusters only change when a value is removed from a tile, so we only need to
+	// look at the values which might have been removed.
+	values, _, _ := changedValuesAndLines(b, changes)
+	for _, v := range MaskBits[values] {
+		if !a.evaluateValue(b, Tile(1<<v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// colorNone is the color of a tile which is not in any cluster.
+const colorNone = uint8(255)
+
+// evaluateValue evaluates the algorithm for the value v.
+func (a *algoColoring) evaluateValue(b *Board, v Tile) bool {
+	links := strongLinks(b, v)
+	if len(links) == 0 {
+		return true
+	}
+
+	// Color the clusters. Colors come in pairs, 2*N and 2*N+1 for cluster N.
+	var tileColors [9 * 9]uint8
+	for i := range tileColors {
+		tileColors[i] = colorNone
+	}
+	var colorTiles [][]uint8
+	for _, sl := range links {
+		if tileColors[sl.ti1] != colorNone {
+			continue
+		}
+		c := uint8(len(colorTiles))
+		colorTiles = append(colorTiles, []uint8{sl.ti1}, nil)
+		tileColors[sl.ti1] = c
+		// walk the cluster, alternating colors across each strong link
+		queue := []uint8{sl.ti1}
+		for len(queue) > 0 {
+			ti := queue[0]
+			queue = queue[1:]
+			nc := tileColors[ti] ^ 1
+			for _, sl := range links {
+				nti := sl.ti2
+				if sl.ti2 == ti {
+					nti = sl.ti1
+				} else if sl.ti1 != ti {
+					continue
+				}
+				if tileColors[nti] == colorNone {
+					tileColors[nti] = nc
+					colorTiles[nc] = append(colorTiles[nc], nti)
+					queue = append(queue, nti)
+					continue
+				}
+				if tileColors[nti] != nc {
+					// A strong link between 2 tiles of the same color. This means there's a
+					// loop of strong links with an odd length, which is impossible.
+					return false
+				}
+			}
+		}
+	}
+
+	// colorSees[c1][c2] indicates whether a tile of color c1 sees a tile of
+	// color c2.
+	colorSees := make([][]bool, len(colorTiles))
+	for c1 := range colorTiles {
+		colorSees[c1] = make([]bool, len(colorTiles))
+		for c2 := range colorTiles {
+			colorSees[c1][c2] = a.colorsSee(colorTiles[c1], colorTiles[c2])
+		}
+	}
+
+	// Color wrap, and the second multi-coloring rule.
+	for c := range colorTiles {
+		falseColor := colorSees[c][c]
+		for c2 := 0; c2 < len(colorTiles) && !falseColor; c2 += 2 {
+			if c2 == c&^1 {
+				// same cluster
+				continue
+			}
+			falseColor = colorSees[c][c2] && colorSees[c][c2+1]
+		}
+		if !falseColor {
+			continue
+		}
+		hit := false
+		for _, ti := range colorTiles[c] {
+			changed, ok := b.unset(ti, v)
+			if !ok {
+				return false
+			}
+			hit = hit || changed
+		}
+		if hit {
+			a.AlgoStats.Hits++
+		}
+	}
+
+	// Color trap, and the first multi-coloring rule.
+	// Both are a tile which sees 2 colors, one of which must be true.
+	for c1 := range colorTiles {
+		for c2 := c1 + 1; c2 < len(colorTiles); c2++ {
+			if c2 != c1^1 && !colorSees[c1^1][c2^1] {
+				// neither the 2 colors of the same cluster, nor 2 colors which can't both
+				// be false
+				continue
+			}
+			hit := false
+			for ti, t := range b.Tiles {
+				if t&v == 0 || tileColors[ti] == uint8(c1) || tileColors[ti] == uint8(c2) {
+					continue
+				}
+				if !a.tileSeesColor(uint8(ti), colorTiles[c1]) || !a.tileSeesColor(uint8(ti), colorTiles[c2]) {
+					continue
+				}
+				changed, ok := b.unset(uint8(ti), v)
+				if !ok {
+					return false
+				}
+				hit = hit || changed
+			}
+			if hit {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+
+	return true
+}
+
+// tileSeesColor indicates whether the tile ti sees any of the given tiles.
+func (a algoColoring) tileSeesColor(ti uint8, tis []uint8) bool {
+	for _, cti := range tis {
+		if tilesSee(ti, cti) {
+			return true
+		}
+	}
+	return false
+}
+
+// colorsSee indicates whether any of the tiles in tis1 sees any of the tiles in
+// tis2.
+func (a algoColoring) colorsSee(tis1, tis2 []uint8) bool {
+	for _, ti := range tis1 {
+		if a.tileSeesColor(ti, tis2) {
+			return true
+		}
+	}
+	return false
+}
+
+// xCyclesDefaultMaxLength is the maximum number of tiles in a loop searched for
+// by algoXCycles, when no maximum is given.
+const xCyclesDefaultMaxLength = 10
+
+// algoXCycles finds X-Cycles (single value nice loops).
+// An X-Cycle is a loop of tiles which can hold a value, where each tile is
+// linked to the next, alternating between strong links (one of the 2 tiles must
+// hold the value) and weak links (at most one of the 2 tiles can hold the
+// value).
+//   - Continuous loop - When the loop alternates all the way around, each weak
+//     link in the loop acts as a strong link. Any tile which sees both ends of a
+//     link in the loop can't hold the value.
+//   - Discontinuous loop, 2 strong links - When the loop alternates all the way
+//     around except at one tile which has 2 strong links, that tile must hold the
+//     value.
+//   - Discontinuous loop, 2 weak links - When the loop alternates all the way
+//     around except at one tile which has 2 weak links, that tile can't hold the
+//     value.
+//
+// https://www.sudokuwiki.org/X_Cycles
+type algoXCycles struct {
+	AlgoStats AlgorithmStats
+	// MaxLength is the maximum number of tiles in a loop. If 0,
+	// xCyclesDefaultMaxLength is used.
+	MaxLength int
+}
+
+func (a algoXCycles) Name() string { return "algoXCycles" }
+
+func (a *algoXCycles) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoXCycles) EvaluateChanges(b *Board, changes []uint8) bool {
+	values, _, _ := changedValuesAndLines(b, changes)
+	for _, v := range MaskBits[values] {
+		if !a.evaluateValue(b, Tile(1<<v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// xCycleSearch holds the state of a search for X-Cycles on a single value.
+type xCycleSearch struct {
+	a *algoXCycles
+	b *Board
+	v Tile
+	// strong holds the tiles each tile is strongly linked to.
+	strong [9 * 9][]uint8
+	// path is the list of tiles in the chain being walked, starting with the tile
+	// the loop would close on.
+	path   []uint8
+	onPath [9 * 9]bool
+	// startIsV is the assumption being made about the first tile in the path.
+	startIsV bool
+	// maxLength is the maximum number of tiles in path.
+	maxLength int
+}
+
+// evaluateValue evaluates the algorithm for the value v.
+func (a *algoXCycles) evaluateValue(b *Board, v Tile) bool {
+	s := xCycleSearch{a: a, b: b, v: v, maxLength: a.MaxLength}
+	if s.maxLength == 0 {
+		s.maxLength = xCyclesDefaultMaxLength
+	}
+	for _, sl := range strongLinks(b, v) {
+		s.strong[sl.ti1] = append(s.strong[sl.ti1], sl.ti2)
+		s.strong[sl.ti2] = append(s.strong[sl.ti2], sl.ti1)
+	}
+
+	for ti := uint8(0); ti < 9*9; ti++ {
+		if b.Tiles[ti]&v == 0 || b.Tiles[ti] == v {
+			continue
+		}
+		for _, startIsV := range []bool{false, true} {
+			if !startIsV && len(s.strong[ti]) == 0 {
+				// Assuming the tile doesn't hold the value, the chain has to start with a
+				// strong link.
+				continue
+			}
+			s.startIsV = startIsV
+			s.path = append(s.path[:0], ti)
+			s.onPath[ti] = true
+			_, ok := s.walk(ti, startIsV)
+			s.onPath[ti] = false
+			if !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// walk extends the path from the tile ti, which is assumed to hold the value
+// if isV is true, or not hold it if false.
+// Returns done as true when a deduction was made about the first tile in the
+// path, meaning there's no point in searching further from it. Returns ok as
+// false if the board is invalid.
+func (s *xCycleSearch) walk(ti uint8, isV bool) (done, ok bool) {
+	startTi := s.path[0]
+	if len(s.path) >= 3 {
+		switch {
+		case !s.startIsV && !isV && s.stronglyLinked(ti, startTi):
+			// If the first tile doesn't hold the value, this one doesn't either, which
+			// would force the first tile to hold the value. So it must.
+			return s.apply(s.b.set(startTi, s.v), true)
+		case s.startIsV && isV && tilesSee(ti, startTi):
+			// If the first tile holds the value, so does this one. But they see each
+			// other, so the first tile can't hold the value.
+			changed, ok := s.b.unset(startTi, s.v)
+			return s.apply(ok, changed)
+		case !s.startIsV && isV && len(s.path) >= 4 && tilesSee(ti, startTi):
+			// A continuous loop.
+			if changed, ok := s.eliminateLoop(); !ok || changed {
+				return s.apply(ok, changed)
+			}
+		}
+	}
+	if len(s.path) == s.maxLength {
+		return false, true
+	}
+
+	if !isV {
+		// this tile doesn't hold the value, so the other end of any strong link must
+		for _, nti := range s.strong[ti] {
+			if done, ok := s.step(nti, true); done || !ok {
+				return done, ok
+			}
+		}
+		return false, true
+	}
+
+	// this tile holds the value, so no peer can
+	for _, nti := range PeerIndices[ti][:] {
+		if len(s.strong[nti]) == 0 || s.b.Tiles[nti]&s.v == 0 {
+			// the chain would go nowhere from this tile
+			continue
+		}
+		if done, ok := s.step(nti, false); done || !ok {
+			return done, ok
+		}
+	}
+	return false, true
+}
+
+// step adds the tile ti to the path and walks from it.
+func (s *xCycleSearch) step(ti uint8, isV bool) (done, ok bool) {
+	if s.onPath[ti] {
+		return false, true
+	}
+	s.path = append(s.path, ti)
+	s.onPath[ti] = true
+	done, ok = s.walk(ti, isV)
+	s.onPath[ti] = false
+	s.path = s.path[:len(s.path)-1]
+	return
+}
+
+// apply records a hit if a deduction changed the board, and returns the result
+// for walk.
+func (s *xCycleSearch) apply(ok, changed bool) (done, _ bool) {
+	if changed {
+		s.a.AlgoStats.Hits++
+	}
+	return true, ok
+}
+
+// stronglyLinked indicates whether there is a strong link between the 2 tiles.
+func (s *xCycleSearch) stronglyLinked(ti1, ti2 uint8) bool {
+	for _, nti := range s.strong[ti1] {
+		if nti == ti2 {
+			return true
+		}
+	}
+	return false
+}
+
+// eliminateLoop removes the value from all tiles which see both ends of any of
+// the links in the path, treating the path as a continuous loop.
+func (s *xCycleSearch) eliminateLoop() (changed, ok bool) {
+	for i, ti1 := range s.path {
+		ti2 := s.path[(i+1)%len(s.path)]
+		for _, nti := range PeerIndices[ti1][:] {
+			if s.onPath[nti] || !tilesSee(nti, ti2) {
+				continue
+			}
+			c, ok := s.b.unset(nti, s.v)
+			if !ok {
+				return changed, false
+			}
+			changed = changed || c
+		}
+	}
+	return changed, true
+}
+
+// xyChainDefaultMaxLength is the maximum number of tiles in a chain searched for
+// by algoXYChain, when no maximum is given.
+const xyChainDefaultMaxLength = 10
+
+// algoXYChain finds XY-Chains.
+// An XY-Chain is a chain of tiles with 2 possible values each, where each tile
+// sees the next, and shares a value with it. If the first tile doesn't hold its
+// value X, then it holds its other value, which means the next tile holds its
+// other value, and so on down the chain. If the last tile in the chain is forced
+// to hold X, then one of the 2 ends of the chain holds X, and any tile which
+// sees both ends can't hold X.
+//
+// The search order is deterministic (by tile index, then by value) so that
+// solving the same board always produces the same stats.
+//
+// https://www.sudokuwiki.org/XY_Chains
+type algoXYChain struct {
+	AlgoStats AlgorithmStats
+	// MaxLength is the maximum number of tiles in a chain. If 0,
+	// xyChainDefaultMaxLength is used.
+	MaxLength int
+}
+
+func (a algoXYChain) Name() string { return "algoXYChain" }
+
+func (a *algoXYChain) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoXYChain) EvaluateChanges(b *Board, changes []uint8) bool {
+	s := xyChainSearch{a: a, b: b, maxLength: a.MaxLength}
+	if s.maxLength == 0 {
+		s.maxLength = xyChainDefaultMaxLength
+	}
+
+	for ti := uint8(0); ti < 9*9; ti++ {
+		t := b.Tiles[ti]
+		if len(MaskBits[t]) != 2 {
+			continue
+		}
+		for x := Tile(1); x < tAny; x = x << 1 {
+			if t&x == 0 {
+				continue
+			}
+			s.x = x
+			s.path = append(s.path[:0], ti)
+			s.onPath[ti] = true
+			ok := s.walk(ti, t&^x)
+			s.onPath[ti] = false
+			if !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// xyChainSearch holds the state of a search for XY-Chains from a single tile.
+type xyChainSearch struct {
+	a *algoXYChain
+	b *Board
+	// x is the value which the first tile in the chain is assumed not to hold.
+	x         Tile
+	path      []uint8
+	onPath    [9 * 9]bool
+	maxLength int
+}
+
+// walk extends the chain from tile ti, which would hold the value v.
+// Returns false if the board is invalid.
+func (s *xyChainSearch) walk(ti uint8, v Tile) bool {
+	if v == s.x && len(s.path) >= 3 {
+		changed, ok := eliminateFromCommonPeers(s.b, s.x, s.path[0], ti)
+		if !ok {
+			return false
+		}
+		if changed {
+			s.a.AlgoStats.Hits++
+		}
+	}
+	if len(s.path) == s.maxLength {
+		return true
+	}
+
+	for _, nti := range PeerIndices[ti][:] {
+		nt := s.b.Tiles[nti]
+		if s.onPath[nti] || nt&v == 0 || len(MaskBits[nt]) != 2 {
+			continue
+		}
+		s.path = append(s.path, nti)
+		s.onPath[nti] = true
+		ok := s.walk(nti, nt&^v)
+		s.onPath[nti] = false
+		s.path = s.path[:len(s.path)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// UniquenessAlgorithms returns new instances of the algorithms which rely on the
+// board having a single solution. These are not part of the default algorithms
+// added by NewBoard, as they can produce incorrect results on boards with
+// multiple solutions. To use them, append them to Board.Algorithms.
+func UniquenessAlgorithms() []Algorithm {
+	return []Algorithm{
+		&algoUniqueRectangle{},
+		&algoBUG{},
+	}
+}
+
+// ExpensiveAlgorithms returns new instances of the algorithms which are too slow,
+// or too rarely useful for their cost, to be part of the default algorithms
+// added by NewBoard. To use them, append them to Board.Algorithms. As the board
+// restarts from the first algorithm after any change, they should be after all
+// other algorithms.
+func ExpensiveAlgorithms() []Algorithm {
+	return []Algorithm{
+		&algoAIC{},
+		&algoPatternOverlay{},
+		&algoExocet{},
+		NewForcingChains(false),
+		&algoDeathBlossom{},
+	}
+}
+
+// algoUniqueRectangle finds Unique Rectangles (types 1 through 6).
+// A unique rectangle is 4 tiles on the corners of a rectangle spanning 2 rows,
+// 2 columns, and 2 regions, which can all hold the same 2 values [A,B]. If the
+// tiles could only hold those 2 values, then they could be swapped, and the
+// board would have 2 solutions. So on a board with a single solution, at least
+// one of the corners must hold some other value.
+// The bi-value corners (those with only [A,B]) are referred to as the floor,
+// and the others as the roof.
+//
+// https://www.sudokuwiki.org/Unique_Rectangles
+type algoUniqueRectangle struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoUniqueRectangle) Name() string { return "algoUniqueRectangle" }
+
+func (a *algoUniqueRectangle) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoUniqueRectangle) EvaluateChanges(b *Board, changes []uint8) bool {
+	for y1 := uint8(0); y1 < 9; y1++ {
+		for y2 := y1 + 1; y2 < 9; y2++ {
+			for x1 := uint8(0); x1 < 9; x1++ {
+				for x2 := x1 + 1; x2 < 9; x2++ {
+					if (y1/3 == y2/3) == (x1/3 == x2/3) {
+						// the rectangle must span exactly 2 regions
+						continue
+					}
+					corners := [4]uint8{
+						xyToIndex(x1, y1),
+						xyToIndex(x2, y1),
+						xyToIndex(x1, y2),
+						xyToIndex(x2, y2),
+					}
+					if !a.evaluateRectangle(b, corners) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// evaluateRectangle evaluates the rectangle with the given corners. The corners
+// are ordered top-left, top-right, bottom-left, bottom-right.
+func (a *algoUniqueRectangle) evaluateRectangle(b *Board, corners [4]uint8) bool {
+	common := tAny
+	for _, ti := range corners {
+		t := b.Tiles[ti]
+		if t.isKnown() {
+			return true
+		}
+		common &= t
+	}
+	if len(MaskBits[common]) < 2 {
+		return true
+	}
+
+	vs := MaskBits[common]
+	for i, v1 := range vs {
+		for _, v2 := range vs[i+1:] {
+			ab := Tile(1<<v1 | 1<<v2)
+			var floor, roof []uint8
+			for _, ti := range corners {
+				if b.Tiles[ti] == ab {
+					floor = append(floor, ti)
+				} else {
+					roof = append(roof, ti)
+				}
+			}
+
+			var changed, ok bool
+			switch len(floor) {
+			case 3:
+				changed, ok = a.type1(b, ab, roof[0])
+			case 2:
+				changed, ok = a.evaluateTwoRoofs(b, ab, corners, floor, roof)
+			case 1:
+				changed, ok = a.type5(b, ab, roof)
+			default:
+				continue
+			}
+			if !ok {
+				return false
+			}
+			if changed {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+	return true
+}
+
+// type1 handles the case where 3 corners are bi-value. The roof must hold
+// something other than A or B.
+func (a algoUniqueRectangle) type1(b *Board, ab Tile, rti uint8) (changed, ok bool) {
+	return b.unset(rti, ab)
+}
+
+// type5 handles the case where all the roof tiles have the same single extra
+// value C (this also covers type 2). One of the roof tiles must hold C, so any
+// tile which sees all of the roof tiles can't.
+func (a algoUniqueRectangle) type5(b *Board, ab Tile, roof []uint8) (changed, ok bool) {
+	c := b.Tiles[roof[0]] &^ ab
+	if len(MaskBits[c]) != 1 {
+		return false, true
+	}
+	for _, rti := range roof[1:] {
+		if b.Tiles[rti]&^ab != c {
+			return false, true
+		}
+	}
+	return eliminateFromCommonPeers(b, c, roof...)
+}
+
+// evaluateTwoRoofs handles the cases where 2 corners are bi-value.
+func (a algoUniqueRectangle) evaluateTwoRoofs(b *Board, ab Tile, corners [4]uint8, floor, roof []uint8) (changed, ok bool) {
+	if changed, ok = a.type5(b, ab, roof); changed || !ok {
+		// type 2 or type 5
+		return
+	}
+
+	if !tilesSee(roof[0], roof[1]) {
+		// The floor & roof are on opposite corners.
+		return a.type6(b, ab, corners, roof)
+	}
+
+	// The roof tiles are in the same row or column, and possibly the same region.
+	for _, idcs := range NeighborSetIndices {
+		if !containsIndex(idcs[:], roof[0]) || !containsIndex(idcs[:], roof[1]) {
+			continue
+		}
+		if changed, ok = a.type4(b, ab, idcs, roof); changed || !ok {
+			return
+		}
+		if changed, ok = a.type3(b, ab, idcs, roof); changed || !ok {
+			return
+		}
+	}
+	return false, true
+}
+
+// type3 handles the case where the roof tiles share a neighbor set, and their
+// extra values form a naked subset with other tiles in the neighbor set. Since
+// one of the roof tiles must hold one of the extra values, the roof tiles act
+// as a single tile within the subset.
+func (a algoUniqueRectangle) type3(b *Board, ab Tile, idcs [9]uint8, roof []uint8) (changed, ok bool) {
+	extra := (b.Tiles[roof[0]] | b.Tiles[roof[1]]) &^ ab
+	if len(MaskBits[extra]) < 2 {
+		return false, true
+	}
+
+	// others is a bit mask of the positions within the neighbor set of the unknown
+	// tiles which are not part of the roof.
+	var others uint16
+	for i, ti := range idcs {
+		if ti != roof[0] && ti != roof[1] && !b.Tiles[ti].isKnown() {
+			others |= 1 << uint16(i)
+		}
+	}
+
+	for subset := others; subset != 0; subset = (subset - 1) & others {
+		size := len(MaskBits[subset])
+		if size > 3 {
+			continue
+		}
+		values := extra
+		for _, i := range MaskBits[subset] {
+			values |= b.Tiles[idcs[i]]
+		}
+		if len(MaskBits[values]) != size+1 {
+			continue
+		}
+		// found a naked subset. Remove its values from the rest of the neighbor set.
+		for _, i := range MaskBits[others&^subset] {
+			c, ok := b.unset(idcs[i], values)
+			if !ok {
+				return changed, false
+			}
+			changed = changed || c
+		}
+		if changed {
+			return changed, true
+		}
+	}
+	return false, true
+}
+
+// type4 handles the case where the roof tiles share a neighbor set, and one of
+// the values is only possible within the roof tiles in that neighbor set. One of
+// the roof tiles must hold that value, so neither roof tile can hold the other
+// value.
+func (a algoUniqueRectangle) type4(b *Board, ab Tile, idcs [9]uint8, roof []uint8) (changed, ok bool) {
+ValueLoop:
+	for _, v := range MaskBits[ab] {
+		u := Tile(1 << v)
+		for _, ti := range idcs {
+			if ti != roof[0] && ti != roof[1] && b.Tiles[ti]&u != 0 {
+				continue ValueLoop
+			}
+		}
+		c1, ok := b.unset(roof[0], ab&^u)
+		if !ok {
+			return false, false
+		}
+		c2, ok := b.unset(roof[1], ab&^u)
+		return c1 || c2, ok
+	}
+	return false, true
+}
+
+// type6 handles the case where the floor tiles are on opposite corners, and one
+// of the values is only possible within the corners in both rows (or both
+// columns). This forms an X-Wing where the value can only be in the floor tiles,
+// as otherwise the roof tiles would both hold it, and the floor tiles would both
+// hold the other value.
+func (a algoUniqueRectangle) type6(b *Board, ab Tile, corners [4]uint8, roof []uint8) (changed, ok bool) {
+	x1, y1 := indexToXY(corners[0])
+	x2, y2 := indexToXY(corners[3])
+	for _, v := range MaskBits[ab] {
+		u := Tile(1 << v)
+		rowMasks := lineValueMasks(b, &RowIndices, u)
+		columnMasks := lineValueMasks(b, &ColumnIndices, u)
+		xMask := uint16(1<<x1 | 1<<x2)
+		yMask := uint16(1<<y1 | 1<<y2)
+		if rowMasks[y1] != xMask || rowMasks[y2] != xMask {
+			if columnMasks[x1] != yMask || columnMasks[x2] != yMask {
+				continue
+			}
+		}
+		c1, ok := b.unset(roof[0], u)
+		if !ok {
+			return false, false
+		}
+		c2, ok := b.unset(roof[1], u)
+		return c1 || c2, ok
+	}
+	return false, true
+}
+
+// algoBUG finds the Bivalue Universal Grave + 1 pattern.
+// A BUG is a board where every unknown tile has 2 possible values, and every
+// possible value appears exactly twice within each row, column, and region. Such
+// a board has either no solution or multiple solutions. So if every unknown
+// tile has 2 possible values except for one tile with 3, and removing one of
+// those 3 values would leave a BUG, then on a board with a single solution that
+// tile must hold that value.
+//
+// https://www.sudokuwiki.org/BUG
+type algoBUG struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoBUG) Name() string { return "algoBUG" }
+
+func (a *algoBUG) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoBUG) EvaluateChanges(b *Board, changes []uint8) bool {
+	bugTi := uint8(255)
+	for ti, t := range b.Tiles {
+		switch len(MaskBits[t]) {
+		case 1, 2:
+			continue
+		case 3:
+			if bugTi != 255 {
+				// more than one tile with 3 possible values
+				return true
+			}
+			bugTi = uint8(ti)
+		default:
+			return true
+		}
+	}
+	if bugTi == 255 {
+		return true
+	}
+
+	for _, v := range MaskBits[b.Tiles[bugTi]] {
+		if !a.isBUG(b, bugTi, Tile(1<<v)) {
+			continue
+		}
+		a.AlgoStats.Hits++
+		return b.set(bugTi, Tile(1<<v))
+	}
+	return true
+}
+
+// isBUG indicates whether the board would be a BUG if the value v were removed
+// from the tile bugTi.
+func (a algoBUG) isBUG(b *Board, bugTi uint8, v Tile) bool {
+	for _, idcs := range NeighborSetIndices {
+		var valueCounts [9]uint8
+		for _, ti := range idcs {
+			t := b.Tiles[ti]
+			if t.isKnown() {
+				continue
+			}
+			if ti == bugTi {
+				t &^= v
+			}
+			for _, tv := range MaskBits[t] {
+				valueCounts[tv]++
+			}
+		}
+		for _, c := range valueCounts {
+			if c != 0 && c != 2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// algoTurbotFish finds the single value patterns of the turbot fish family:
+// Skyscraper, 2-String Kite, & Empty Rectangle.
+//
+// Skyscrapers & 2-String Kites are both made of 2 strong links, where one end
+// of the first link sees one end of the second. If the far end of the first link
+// doesn't hold the value, the near end does, so the near end of the second link
+// doesn't, so the far end of the second link does. So one of the 2 far ends
+// holds the value, and any tile which sees both can't.
+//
+// An Empty Rectangle is a region where the possible tiles for the value all lie
+// within one row and one column. If a strong link has one end in that row
+// (outside the region), then the tile at the intersection of the region's column
+// and the row of the other end of the link can't hold the value. The same goes
+// with rows & columns swapped.
+//
+// https://www.sudokuwiki.org/Turbot_Fish
+// https://www.sudokuwiki.org/Empty_Rectangles
+type algoTurbotFish struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoTurbotFish) Name() string { return "algoTurbotFish" }
+
+func (a *algoTurbotFish) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoTurbotFish) EvaluateChanges(b *Board, changes []uint8) bool {
+	values, _, _ := changedValuesAndLines(b, changes)
+	for _, v := range MaskBits[values] {
+		vt := Tile(1 << v)
+		links := strongLinks(b, vt)
+		if !a.evaluateKites(b, vt, links) {
+			return false
+		}
+		if !a.evaluateEmptyRectangles(b, vt, links) {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateKites finds Skyscrapers & 2-String Kites for the value v.
+func (a *algoTurbotFish) evaluateKites(b *Board, v Tile, links []strongLink) bool {
+	for i, sl1 := range links {
+		for _, sl2 := range links[i+1:] {
+			if sl1.ti1 == sl2.ti1 || sl1.ti1 == sl2.ti2 || sl1.ti2 == sl2.ti1 || sl1.ti2 == sl2.ti2 {
+				continue
+			}
+			for _, ends1 := range [2][2]uint8{{sl1.ti1, sl1.ti2}, {sl1.ti2, sl1.ti1}} {
+				for _, ends2 := range [2][2]uint8{{sl2.ti1, sl2.ti2}, {sl2.ti2, sl2.ti1}} {
+					// ends[0] is the near end, ends[1] is the far end
+					if !tilesSee(ends1[0], ends2[0]) {
+						continue
+					}
+					changed, ok := eliminateFromCommonPeers(b, v, ends1[1], ends2[1])
+					if !ok {
+						return false
+					}
+					if changed {
+						a.AlgoStats.Hits++
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// evaluateEmptyRectangles finds Empty Rectangles for the value v.
+func (a *algoTurbotFish) evaluateEmptyRectangles(b *Board, v Tile, links []strongLink) bool {
+RegionLoop:
+	for rgnIdx, rgnIndices := range RegionIndices {
+		var rows, columns uint16
+		var tis []uint8
+		for _, ti := range rgnIndices {
+			t := b.Tiles[ti]
+			if t == v {
+				continue RegionLoop
+			}
+			if t&v == 0 {
+				continue
+			}
+			x, y := indexToXY(ti)
+			rows |= 1 << y
+			columns |= 1 << x
+			tis = append(tis, ti)
+		}
+		if len(MaskBits[rows]) < 2 || len(MaskBits[columns]) < 2 {
+			// All in one row or column (or no possible tiles). This is handled by
+			// algoOnlyRow.
+			continue
+		}
+
+		for _, ery := range MaskBits[rows] {
+			for _, erx := range MaskBits[columns] {
+				inCross := true
+				for _, ti := range tis {
+					x, y := indexToXY(ti)
+					if x != erx && y != ery {
+						inCross = false
+						break
+					}
+				}
+				if !inCross {
+					continue
+				}
+
+				for _, sl := range links {
+					for _, ends := range [2][2]uint8{{sl.ti1, sl.ti2}, {sl.ti2, sl.ti1}} {
+						ti := a.emptyRectangleTarget(uint8(rgnIdx), erx, ery, ends[0], ends[1])
+						if ti == 255 {
+							continue
+						}
+						changed, ok := b.unset(ti, v)
+						if !ok {
+							return false
+						}
+						if changed {
+							a.AlgoStats.Hits++
+						}
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// emptyRectangleTarget finds the tile which can't hold the value given an empty
+// rectangle in region rgnIdx, crossing at column erx and row ery, and a strong
+// link from ti1 to ti2. If the link doesn't form the pattern, 255 is returned.
+func (a algoTurbotFish) emptyRectangleTarget(rgnIdx, erx, ery, ti1, ti2 uint8) uint8 {
+	x1, y1 := indexToXY(ti1)
+	x2, y2 := indexToXY(ti2)
+	rgnX, rgnY := rgnIdx%3, rgnIdx/3
+	switch {
+	case y1 == ery && x1 == x2 && x1/3 != rgnX && y2/3 != rgnY:
+		// ti1 in the region's row, with the link along a column
+		return xyToIndex(erx, y2)
+	case x1 == erx && y1 == y2 && y1/3 != rgnY && x2/3 != rgnX:
+		// ti1 in the region's column, with the link along a row
+		return xyToIndex(x2, ery)
+	}
+	return 255
+}
+
+// almostLockedSet is a set of N tiles within a neighbor set, which between them
+// have N+1 possible values.
+type almostLockedSet struct {
+	tiles  tileSet
+	values Tile
+	// valueTiles holds the tiles within the set which can hold each value (0-8).
+	valueTiles [9]tileSet
+	// valuePeers holds the tiles which see every tile in valueTiles, for each
+	// value. It is empty for values the set can't hold.
+	valuePeers [9]tileSet
+}
+
+// almostLockedSets finds all the almost locked sets on the board with at most
+// maxSize tiles. The sets are returned in a deterministic order, and a set of
+// tiles which is within multiple neighbor sets is only returned once.
+func almostLockedSets(b *Board, maxSize int) []almostLockedSet {
+	var alss []almostLockedSet
+	seen := map[tileSet]bool{}
+	for _, idcs := range NeighborSetIndices {
+		// valueTileIndices is a list of values to a bit mask of positions within
+		// the neighbor set which can hold that value. The same as within
+		// algoHiddenSubset.
+		valueTileIndices := [9]uint16{}
+		var unknowns uint16
+		for i, ti := range idcs {
+			t := b.Tiles[ti]
+			if t.isKnown() {
+				continue
+			}
+			unknowns |= 1 << uint16(i)
+			for _, v := range MaskBits[t] {
+				valueTileIndices[v] |= 1 << uint16(i)
+			}
+		}
+
+		for subset := unknowns; subset != 0; subset = (subset - 1) & unknowns {
+			size := len(MaskBits[subset])
+			if size > maxSize {
+				continue
+			}
+			var values Tile
+			for _, i := range MaskBits[subset] {
+				values |= b.Tiles[idcs[i]]
+			}
+			if len(MaskBits[values]) != size+1 {
+				continue
+			}
+
+			als := almostLockedSet{values: values}
+			for _, i := range MaskBits[subset] {
+				als.tiles.add(idcs[i])
+			}
+			if seen[als.tiles] {
+				continue
+			}
+			seen[als.tiles] = true
+			for _, v := range MaskBits[values] {
+				als.valuePeers[v] = tileSet{^uint64(0), ^uint64(0)}
+				for _, i := range MaskBits[valueTileIndices[v]&subset] {
+					als.valueTiles[v].add(idcs[i])
+					als.valuePeers[v] = als.valuePeers[v].and(PeerSets[idcs[i]])
+				}
+			}
+			alss = append(alss, als)
+		}
+	}
+	return alss
+}
+
+// restrictedCommons returns the values which are restricted commons between the
+// 2 sets. A restricted common is a value where every tile which can hold it in
+// one set sees every tile which can hold it in the other set. So at most one of
+// the 2 sets can hold the value.
+func (als almostLockedSet) restrictedCommons(als2 almostLockedSet) Tile {
+	var rccs Tile
+	for _, v := range MaskBits[als.values&als2.values] {
+		if als2.valueTiles[v].andNot(als.valuePeers[v]).empty() {
+			rccs |= 1 << v
+		}
+	}
+	return rccs
+}
+
+// alsDefaultMaxSize is the maximum number of tiles in the almost locked sets
+// used by algoALS, when no maximum is given.
+const alsDefaultMaxSize = 4
+
+// algoALS finds patterns of almost locked sets (ALS).
+// An ALS is N tiles within a neighbor set with N+1 possible values. If any one
+// of the values is removed, the rest are locked into the set.
+//
+// ALS-XZ: 2 ALSs A & B, with a restricted common value X (every tile which can
+// hold X in A sees every tile which can hold X in B). X can only be in one of
+// them, so the other is locked. So if both sets can hold some other value Z, one
+// of them must, and any tile which sees all the Z tiles of both sets can't.
+// When there are 2 restricted commons, both sets are locked, and every value can
+// be eliminated from the tiles outside the sets which see all of its tiles.
+//
+// ALS-XY-Wing: 3 ALSs A, B, & C, where A & C have a restricted common X, and B &
+// C have a different restricted common Y. If A doesn't hold Z, it's locked, so
+// it holds X, so C doesn't, so C is locked and holds Y, so B doesn't and is
+// locked, so B holds Z. So either A or B holds Z, and any tile which sees all
+// the Z tiles of both can't.
+//
+// https://www.sudokuwiki.org/Almost_Locked_Sets
+// https://www.sudokuwiki.org/ALS_XY_Wing
+type algoALS struct {
+	AlgoStats AlgorithmStats
+	// MaxSize is the maximum number of tiles in an ALS. If 0, alsDefaultMaxSize
+	// is used.
+	MaxSize int
+}
+
+func (a algoALS) Name() string { return "algoALS" }
+
+func (a *algoALS) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoALS) EvaluateChanges(b *Board, changes []uint8) bool {
+	maxSize := a.MaxSize
+	if maxSize == 0 {
+		maxSize = alsDefaultMaxSize
+	}
+	alss := almostLockedSets(b, maxSize)
+
+	// A pattern can only have new eliminations if one of its sets contains a tile
+	// which has changed, as the sets & restricted commons depend only on their own
+	// tiles.
+	var changed tileSet
+	for _, ti := range changes {
+		changed.add(ti)
+	}
+	touched := make([]bool, len(alss))
+	for i, als := range alss {
+		touched[i] = !als.tiles.and(changed).empty()
+	}
+
+	// rccs[i] holds the sets which have restricted commons with set i.
+	type rccLink struct {
+		j    int
+		rccs Tile
+	}
+	rccs := make([][]rccLink, len(alss))
+
+	// ALS-XZ
+	for i, als1 := range alss {
+		for j := i + 1; j < len(alss); j++ {
+			als2 := alss[j]
+			if !als1.tiles.and(als2.tiles).empty() {
+				continue
+			}
+			xs := als1.restrictedCommons(als2)
+			if xs == 0 {
+				continue
+			}
+			rccs[i] = append(rccs[i], rccLink{j, xs})
+			rccs[j] = append(rccs[j], rccLink{i, xs})
+			if !touched[i] && !touched[j] {
+				continue
+			}
+
+			changed, ok := a.eliminateXZ(b, als1, als2, xs)
+			if !ok {
+				return false
+			}
+			if changed {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+
+	// ALS-XY-Wing, with C as the pivot
+	for c, links := range rccs {
+		for i, l1 := range links {
+			for _, l2 := range links[i+1:] {
+				if !touched[c] && !touched[l1.j] && !touched[l2.j] {
+					continue
+				}
+				alsA, alsB := alss[l1.j], alss[l2.j]
+				if !alsA.tiles.and(alsB.tiles).empty() {
+					continue
+				}
+				if len(MaskBits[l1.rccs|l2.rccs]) < 2 {
+					// need different restricted commons for X & Y
+					continue
+				}
+				changed := false
+				for _, z := range MaskBits[alsA.values&alsB.values] {
+					zt := Tile(1 << z)
+					xs, ys := l1.rccs&^zt, l2.rccs&^zt
+					if xs == 0 || ys == 0 || xs == ys && len(MaskBits[xs]) == 1 {
+						// no choice of X & Y which are different from each other and from Z
+						continue
+					}
+					c, ok := eliminateFromTiles(b, zt, alsA.valuePeers[z].and(alsB.valuePeers[z]).andNot(alsA.tiles.or(alsB.tiles)))
+					if !ok {
+						return false
+					}
+					changed = changed || c
+				}
+				if changed {
+					a.AlgoStats.Hits++
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// eliminateXZ makes the ALS-XZ eliminations for 2 sets with the restricted
+// commons xs.
+func (a algoALS) eliminateXZ(b *Board, als1, als2 almostLockedSet, xs Tile) (changed, ok bool) {
+	both := als1.tiles.or(als2.tiles)
+	if len(MaskBits[xs]) == 1 {
+		for _, z := range MaskBits[als1.values&als2.values&^xs] {
+			c, ok := eliminateFromTiles(b, Tile(1<<z), als1.valuePeers[z].and(als2.valuePeers[z]).andNot(both))
+			if !ok {
+				return changed, false
+			}
+			changed = changed || c
+		}
+		return changed, true
+	}
+
+	// Doubly linked. Both sets are locked.
+	for _, v := range MaskBits[als1.values|als2.values] {
+		var c bool
+		var ok bool
+		if xs&(1<<v) != 0 {
+			c, ok = eliminateFromTiles(b, Tile(1<<v), als1.valuePeers[v].and(als2.valuePeers[v]).andNot(both))
+		} else {
+			c, ok = eliminateFromTiles(b, Tile(1<<v), als1.valuePeers[v].andNot(both))
+			if ok {
+				var c2 bool
+				c2, ok = eliminateFromTiles(b, Tile(1<<v), als2.valuePeers[v].andNot(both))
+				c = c || c2
+			}
+		}
+		if !ok {
+			return changed, false
+		}
+		changed = changed || c
+	}
+	return changed, true
+}
+
+// eliminateFromTiles removes the value v from all the tiles in ts.
+func eliminateFromTiles(b *Board, v Tile, ts tileSet) (changed, ok bool) {
+	for !ts.empty() {
+		ti := ts.pop()
+		if b.Tiles[ti]&v == 0 {
+			continue
+		}
+		c, ok := b.unset(ti, v)
+		if !ok {
+			return changed, false
+		}
+		changed = changed || c
+	}
+	return changed, true
+}
+
+// algoSueDeCoq finds Sue de Coq patterns (two-sector disjoint subsets).
+// Take N tiles C within the intersection of a region and a row/column, which
+// between them have at least N+2 possible values V. Then take some tiles D from
+// the rest of the row/column, and some tiles E from the rest of the region, where
+// the values of D & E don't overlap. If there are as many tiles in C, D, & E as
+// there are values between them, each value must be held by exactly one tile.
+// So:
+//   - The values of D, & the values of V not in E, must be within C & D, and are
+//     eliminated from the rest of the row/column.
+//   - The values of E, & the values of V not in D, must be within C & E, and are
+//     eliminated from the rest of the region.
+//
+// https://www.sudokuwiki.org/Sue_De_Coq
+type algoSueDeCoq struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoSueDeCoq) Name() string { return "algoSueDeCoq" }
+
+func (a *algoSueDeCoq) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoSueDeCoq) EvaluateChanges(b *Board, changes []uint8) bool {
+	for si, seg := range BoxLineSegments {
+		lineIndices := RowIndices[seg[0]/9]
+		if si >= len(BoxLineSegments)/2 {
+			lineIndices = ColumnIndices[seg[0]%9]
+		}
+		rgnIndices := RegionIndices[tileIndexToRegionIndex(seg[0])]
+
+		var unknowns []uint8
+		for _, ti := range seg {
+			if !b.Tiles[ti].isKnown() {
+				unknowns = append(unknowns, ti)
+			}
+		}
+		if len(unknowns) < 2 {
+			continue
+		}
+
+		for cMask := uint16(1)<<uint16(len(unknowns)) - 1; cMask != 0; cMask-- {
+			if len(MaskBits[cMask]) < 2 {
+				continue
+			}
+			var cTiles []uint8
+			for _, i := range MaskBits[cMask] {
+				cTiles = append(cTiles, unknowns[i])
+			}
+			changed, ok := a.evaluate(b, seg, cTiles, lineIndices, rgnIndices)
+			if !ok {
+				return false
+			}
+			if changed {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+	return true
+}
+
+// evaluate looks for the tiles D & E which complete a Sue de Coq pattern with the
+// tiles cTiles within the intersection seg, and makes the eliminations for the
+// first one found.
+func (a algoSueDeCoq) evaluate(b *Board, seg [3]uint8, cTiles []uint8, lineIndices, rgnIndices [9]uint8) (changed, ok bool) {
+	var cValues Tile
+	for _, ti := range cTiles {
+		cValues |= b.Tiles[ti]
+	}
+	if len(MaskBits[cValues]) < len(cTiles)+2 {
+		return false, true
+	}
+
+	// the unknown tiles outside the intersection which share a value with C
+	restTiles := func(idcs [9]uint8) []uint8 {
+		var tis []uint8
+		for _, ti := range idcs {
+			t := b.Tiles[ti]
+			if !containsIndex(seg[:], ti) && !t.isKnown() && t&cValues != 0 {
+				tis = append(tis, ti)
+			}
+		}
+		return tis
+	}
+	lineRest := restTiles(lineIndices)
+	rgnRest := restTiles(rgnIndices)
+	if len(lineRest) == 0 || len(rgnRest) == 0 {
+		return false, true
+	}
+
+	subsetValues := func(tis []uint8, mask uint16) (values Tile) {
+		for _, i := range MaskBits[mask] {
+			values |= b.Tiles[tis[i]]
+		}
+		return values
+	}
+	for dMask := uint16(1); dMask < 1<<uint16(len(lineRest)); dMask++ {
+		dValues := subsetValues(lineRest, dMask)
+		for eMask := uint16(1); eMask < 1<<uint16(len(rgnRest)); eMask++ {
+			eValues := subsetValues(rgnRest, eMask)
+			if dValues&eValues != 0 {
+				continue
+			}
+			if len(cTiles)+len(MaskBits[dMask])+len(MaskBits[eMask]) != len(MaskBits[cValues|dValues|eValues]) {
+				continue
+			}
+
+			var dTiles, eTiles []uint8
+			for _, i := range MaskBits[dMask] {
+				dTiles = append(dTiles, lineRest[i])
+			}
+			for _, i := range MaskBits[eMask] {
+				eTiles = append(eTiles, rgnRest[i])
+			}
+			for _, ti := range lineIndices {
+				if containsIndex(cTiles, ti) || containsIndex(dTiles, ti) {
+					continue
+				}
+				c, ok := b.unset(ti, dValues|cValues&^eValues)
+				if !ok {
+					return changed, false
+				}
+				changed = changed || c
+			}
+			for _, ti := range rgnIndices {
+				if containsIndex(cTiles, ti) || containsIndex(eTiles, ti) {
+					continue
+				}
+				c, ok := b.unset(ti, eValues|cValues&^dValues)
+				if !ok {
+					return changed, false
+				}
+				changed = changed || c
+			}
+			return changed, true
+		}
+	}
+	return false, true
+}
+
+// deathBlossomDefaultBudget is the maximum time algoDeathBlossom spends within a
+// single call to EvaluateChanges, when no budget is given.
+const deathBlossomDefaultBudget = 20 * time.Millisecond
+
+// algoDeathBlossom finds Death Blossoms.
+// A death blossom is a stem tile, and a petal ALS (almost locked set) for each
+// of the stem's possible values, where every tile of the petal which can hold
+// that value sees the stem. Whichever value the stem holds, that petal loses the
+// value and becomes locked. So if all the petals can hold some value Z, which
+// the stem can't, one of the petals must hold it, and any tile which sees all
+// the Z tiles of every petal can't.
+//
+// This is expensive, as the number of combinations of petals can be huge. So the
+// search is limited to Budget per call, and the next call continues from where
+// the last one stopped. At least one combination is tried per call, so the
+// search always progresses. Like all algorithms, the time spent is recorded in
+// AlgorithmStats.Duration.
+//
+// It is not part of the default algorithms added by NewBoard. See
+// ExpensiveAlgorithms.
+//
+// https://www.sudokuwiki.org/Death_Blossom
+type algoDeathBlossom struct {
+	AlgoStats AlgorithmStats
+	// MaxSize is the maximum number of tiles in a petal. If 0, alsDefaultMaxSize is
+	// used.
+	MaxSize int
+	// Budget is the maximum time to spend within a single call to
+	// EvaluateChanges. If 0, deathBlossomDefaultBudget is used.
+	Budget time.Duration
+
+	// nextStem is the stem the search starts from.
+	nextStem uint8
+	// resume is the position within the search of nextStem to continue from, as
+	// for deathBlossomSearch.resume.
+	resume []int
+}
+
+func (a algoDeathBlossom) Name() string { return "algoDeathBlossom" }
+
+func (a *algoDeathBlossom) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoDeathBlossom) EvaluateChanges(b *Board, changes []uint8) bool {
+	maxSize := a.MaxSize
+	if maxSize == 0 {
+		maxSize = alsDefaultMaxSize
+	}
+	budget := a.Budget
+	if budget == 0 {
+		budget = deathBlossomDefaultBudget
+	}
+
+	s := deathBlossomSearch{
+		b:        b,
+		alss:     almostLockedSets(b, maxSize),
+		deadline: time.Now().Add(budget),
+		resume:   a.resume,
+	}
+	a.resume = nil
+StemLoop:
+	for i := uint8(0); i < 9*9; i++ {
+		stem := (a.nextStem + i) % (9 * 9)
+		if s.tried > 0 && time.Now().After(s.deadline) {
+			// out of time. Pick up from here next time.
+			a.nextStem = stem
+			break
+		}
+		if i > 0 {
+			s.resume = nil
+		}
+
+		st := b.Tiles[stem]
+		if st.isKnown() {
+			continue
+		}
+		s.stem = stem
+		s.stemValues = MaskBits[st]
+		for _, v := range s.stemValues {
+			s.petals[v] = s.petals[v][:0]
+			for ai, als := range s.alss {
+				if als.tiles.has(stem) || als.values&^st == 0 {
+					continue
+				}
+				if vts := als.valueTiles[v]; vts.empty() || !vts.andNot(PeerSets[stem]).empty() {
+					continue
+				}
+				s.petals[v] = append(s.petals[v], ai)
+			}
+			if len(s.petals[v]) == 0 {
+				continue StemLoop
+			}
+		}
+
+		if !s.walk(0, tAny&^st) {
+			return false
+		}
+		if s.stopped != nil {
+			// out of time part way through the stem. Pick up from the same place next
+			// time.
+			a.nextStem = stem
+			a.resume = s.stopped
+			break
+		}
+	}
+	a.AlgoStats.Hits += s.hits
+
+	return true
+}
+
+// deathBlossomSearch holds the state of the search for death blossoms around a
+// stem.
+type deathBlossomSearch struct {
+	b        *Board
+	alss     []almostLockedSet
+	deadline time.Time
+
+	stem       uint8
+	stemValues []uint8
+	// petals holds the indices of the ALSs which can be the petal for each value.
+	petals [9][]int
+	// chosen holds the indices of the ALSs chosen as petals so far.
+	chosen []int
+	// path holds the positions within petals of the ALSs in chosen.
+	path []int
+
+	// resume is the path to continue the search from. The last position is the
+	// first petal not yet tried, and the others are petals which were chosen.
+	resume []int
+	// stopped is the path where the search stopped when it ran out of time, in
+	// the same form as resume.
+	stopped []int
+	// tried is the number of petals tried.
+	tried int
+
+	hits uint
+}
+
+// walk chooses a petal for the stem value at index i of stemValues, and then
+// recurses to the next value. zs is the values common to all the petals chosen
+// so far.
+func (s *deathBlossomSearch) walk(i int, zs Tile) bool {
+	if i == len(s.stemValues) {
+		return s.apply(zs)
+	}
+	petals := s.petals[s.stemValues[i]]
+	start := 0
+	// resuming indicates whether the first petal was already chosen when the
+	// search stopped, and the search continues below it.
+	resuming := false
+	if s.resume != nil {
+		if i < len(s.resume) {
+			start = s.resume[i]
+		}
+		resuming = i+1 < len(s.resume)
+		if !resuming {
+			s.resume = nil
+		}
+	}
+	for k := start; k < len(petals); k++ {
+		if k > start {
+			s.resume = nil
+		}
+		if k > start || !resuming {
+			if s.tried > 0 && time.Now().After(s.deadline) {
+				s.stopped = append(append([]int(nil), s.path...), k)
+				return true
+			}
+			s.tried++
+		}
+
+		ai := petals[k]
+		nzs := zs & s.alss[ai].values
+		if nzs == 0 {
+			continue
+		}
+		s.chosen = append(s.chosen, ai)
+		s.path = append(s.path, k)
+		ok := s.walk(i+1, nzs)
+		s.chosen = s.chosen[:len(s.chosen)-1]
+		s.path = s.path[:len(s.path)-1]
+		if !ok {
+			return false
+		}
+		if s.stopped != nil {
+			return true
+		}
+	}
+	return true
+}
+
+// apply makes the eliminations for the chosen petals, where zs is the values
+// common to all of them.
+func (s *deathBlossomSearch) apply(zs Tile) bool {
+	var exclude tileSet
+	exclude.add(s.stem)
+	for _, ai := range s.chosen {
+		exclude = exclude.or(s.alss[ai].tiles)
+	}
+
+	changed := false
+	for _, z := range MaskBits[zs] {
+		zPeers := tileSet{^uint64(0), ^uint64(0)}
+		for _, ai := range s.chosen {
+			zPeers = zPeers.and(s.alss[ai].valuePeers[z])
+		}
+		c, ok := eliminateFromTiles(s.b, Tile(1<<z), zPeers.andNot(exclude))
+		if !ok {
+			return false
+		}
+		changed = changed || c
+	}
+	if changed {
+		s.hits++
+	}
+	return true
+}
+
+// algoAlignedPairExclusion finds aligned pair exclusions.
+// Take 2 unknown tiles which see each other. Each combination of their possible
+// values is a pair the 2 tiles might hold (excluding pairs of the same value, as
+// they see each other). If a tile which sees both of them has only those 2
+// possible values, the pair is impossible, as it would leave that tile with no
+// value. Any value of either tile which is not part of a possible pair is
+// eliminated.
+//
+// https://www.sudokuwiki.org/Aligned_Pair_Exclusion
+type algoAlignedPairExclusion struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoAlignedPairExclusion) Name() string { return "algoAlignedPairExclusion" }
+
+func (a *algoAlignedPairExclusion) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoAlignedPairExclusion) EvaluateChanges(b *Board, changes []uint8) bool {
+	for ti1 := uint8(0); ti1 < 9*9; ti1++ {
+		if b.Tiles[ti1].isKnown() {
+			continue
+		}
+		for _, ti2 := range PeerIndices[ti1][:] {
+			if ti2 < ti1 || b.Tiles[ti2].isKnown() {
+				// pairs are only checked once, from the lower tile index
+				continue
+			}
+
+			// excluded holds the pairs of values held by the bi-value tiles which see
+			// both tiles.
+			var excluded []Tile
+			for _, nti := range PeerSets[ti1].and(PeerSets[ti2]).indices() {
+				if nt := b.Tiles[nti]; len(MaskBits[nt]) == 2 {
+					excluded = append(excluded, nt)
+				}
+			}
+			if len(excluded) == 0 {
+				continue
+			}
+
+			t1, t2 := b.Tiles[ti1], b.Tiles[ti2]
+			// the values of each tile which are part of at least one possible pair
+			var valid1, valid2 Tile
+			for _, v1 := range MaskBits[t1] {
+			PairLoop:
+				for _, v2 := range MaskBits[t2] {
+					if v1 == v2 {
+						continue
+					}
+					pair := Tile(1<<v1 | 1<<v2)
+					for _, et := range excluded {
+						if et == pair {
+							continue PairLoop
+						}
+					}
+					valid1 |= 1 << v1
+					valid2 |= 1 << v2
+				}
+			}
+			if valid1 == t1 && valid2 == t2 {
+				continue
+			}
+
+			if !b.set(ti1, valid1) || !b.set(ti2, valid2) {
+				// invalid board configuration
+				return false
+			}
+			a.AlgoStats.Hits++
+		}
+	}
+	return true
+}
+
+// algoPatternOverlay finds eliminations using pattern templates (pattern
+// overlay method). For each value, every placement of the value on the board
+// (see PatternTemplates) is checked against the tiles which can hold it. Tiles
+// which aren't in any of the templates which fit can't hold the value, and tiles
+// which are in all of them must hold the value.
+//
+// It is not part of the default algorithms added by NewBoard. See
+// ExpensiveAlgorithms.
+//
+// http://sudopedia.enjoysudoku.com/Pattern_Overlay_Method.html
+type algoPatternOverlay struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoPatternOverlay) Name() string { return "algoPatternOverlay" }
+
+func (a *algoPatternOverlay) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoPatternOverlay) EvaluateChanges(b *Board, changes []uint8) bool {
+	// The templates which fit a value can only have changed if the value was
+	// removed from some tile.
+	values, _, _ := changedValuesAndLines(b, changes)
+	for _, v := range MaskBits[values] {
+		vt := Tile(1 << v)
+
+		// possible is the tiles which can hold the value, and known is the tiles
+		// which do hold it.
+		var possible, known tileSet
+		for ti, t := range b.Tiles {
+			if t&vt == 0 {
+				continue
+			}
+			possible.add(uint8(ti))
+			if t == vt {
+				known.add(uint8(ti))
+			}
+		}
+		if known == possible {
+			// already solved
+			continue
+		}
+
+		// union is the tiles within any of the templates which fit, and
+		// intersection the tiles within all of them.
+		var union tileSet
+		intersection := tileSet{^uint64(0), ^uint64(0)}
+		fits := false
+		for _, ts := range PatternTemplates {
+			if !ts.andNot(possible).empty() || !known.andNot(ts).empty() {
+				continue
+			}
+			fits = true
+			union = union.or(ts)
+			intersection = intersection.and(ts)
+		}
+		if !fits {
+			// no valid placement of the value
+			return false
+		}
+
+		changed := false
+		for _, ti := range possible.andNot(union).indices() {
+			if !b.set(ti, ^vt) {
+				// invalid board configuration
+				return false
+			}
+			changed = true
+		}
+		for _, ti := range intersection.andNot(known).indices() {
+			if !b.set(ti, vt) {
+				// invalid board configuration
+				return false
+			}
+			changed = true
+		}
+		if changed {
+			a.AlgoStats.Hits++
+		}
+	}
+	return true
+}
+
+// algoExocet finds Junior Exocet (JExocet) patterns.
+// Within a band (3 rows, or 3 columns when transposed):
+//   - 2 base tiles in the same row of one region, with 3 or 4 possible values
+//     between them (the base values).
+//   - 2 target tiles, one in each of the other 2 regions of the band, one in
+//     each of the other 2 rows.
+//   - The S columns are the columns of the targets, plus the column of the base
+//     region which holds neither base tile. The other tiles of the S columns
+//     within the band (the mirror of each target) can't hold any base value.
+//   - Outside the band, the tiles of the S columns which can hold each base
+//     value are covered by 2 lines (rows or S columns).
+//
+// Whichever value a base tile holds must appear once in each of the 3 S columns,
+// but at most twice outside the band. It can't be in the base row or region
+// elsewhere, or in the mirrors, so it must be in one of the targets. So the
+// targets hold the same 2 values as the base tiles. Any value which isn't a base
+// value is eliminated from the targets, and any base value which neither target
+// can hold is eliminated from the base tiles.
+//
+// It is not part of the default algorithms added by NewBoard. See
+// ExpensiveAlgorithms.
+//
+// http://sudopedia.enjoysudoku.com/Exocet.html
+type algoExocet struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoExocet) Name() string { return "algoExocet" }
+
+func (a *algoExocet) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoExocet) EvaluateChanges(b *Board, changes []uint8) bool {
+	for _, transposed := range []bool{false, true} {
+		// at returns the tile index of the given band coordinates, where x is
+		// across the band, and y along it.
+		at := func(x, y uint8) uint8 {
+			if transposed {
+				return xyToIndex(y, x)
+			}
+			return xyToIndex(x, y)
+		}
+
+		for band := uint8(0); band < 3; band++ {
+			for baseRgn := uint8(0); baseRgn < 3; baseRgn++ {
+				for baseY := band * 3; baseY < band*3+3; baseY++ {
+					for escape := uint8(0); escape < 3; escape++ {
+						var baseXs []uint8
+						for x := baseRgn * 3; x < baseRgn*3+3; x++ {
+							if x != baseRgn*3+escape {
+								baseXs = append(baseXs, x)
+							}
+						}
+						base1, base2 := at(baseXs[0], baseY), at(baseXs[1], baseY)
+						t1, t2 := b.Tiles[base1], b.Tiles[base2]
+						if t1.isKnown() || t2.isKnown() {
+							continue
+						}
+						if n := len(MaskBits[t1|t2]); n < 3 || n > 4 {
+							continue
+						}
+						if !a.evaluateBase(b, at, band, baseRgn, baseY, baseRgn*3+escape, base1, base2) {
+							return false
+						}
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// evaluateBase looks for the targets which complete a pattern with the given
+// base tiles, and makes the eliminations.
+func (a *algoExocet) evaluateBase(b *Board, at func(x, y uint8) uint8, band, baseRgn, baseY, escapeX uint8, base1, base2 uint8) bool {
+	baseValues := b.Tiles[base1] | b.Tiles[base2]
+
+	// the other 2 rows of the band, and the other 2 regions
+	var ys, rgns []uint8
+	for i := uint8(0); i < 3; i++ {
+		if y := band*3 + i; y != baseY {
+			ys = append(ys, y)
+		}
+		if i != baseRgn {
+			rgns = append(rgns, i)
+		}
+	}
+
+	for yi := range ys {
+		y1, y2 := ys[yi], ys[1-yi]
+		for x1 := rgns[0] * 3; x1 < rgns[0]*3+3; x1++ {
+			for x2 := rgns[1] * 3; x2 < rgns[1]*3+3; x2++ {
+				target1, target2 := at(x1, y1), at(x2, y2)
+				tt1, tt2 := b.Tiles[target1], b.Tiles[target2]
+				if tt1.isKnown() || tt2.isKnown() || tt1&baseValues == 0 || tt2&baseValues == 0 {
+					continue
+				}
+				// the mirrors
+				if b.Tiles[at(x1, y2)]&baseValues != 0 || b.Tiles[at(x2, y1)]&baseValues != 0 {
+					continue
+				}
+				if !a.covered(b, at, band, [3]uint8{x1, x2, escapeX}, baseValues) {
+					continue
+				}
+
+				// it's a match
+				changed1, ok := b.unset(target1, ^baseValues&tAny)
+				if !ok {
+					return false
+				}
+				changed2, ok := b.unset(target2, ^baseValues&tAny)
+				if !ok {
+					return false
+				}
+				targetValues := b.Tiles[target1] | b.Tiles[target2]
+				changed3, ok := b.unset(base1, ^targetValues&tAny)
+				if !ok {
+					return false
+				}
+				changed4, ok := b.unset(base2, ^targetValues&tAny)
+				if !ok {
+					return false
+				}
+				if changed1 || changed2 || changed3 || changed4 {
+					a.AlgoStats.Hits++
+				}
+				return true
+			}
+		}
+	}
+	return true
+}
+
+// covered indicates whether, for each base value, the tiles of the S columns
+// outside the band which can hold the value are covered by 2 lines.
+func (a algoExocet) covered(b *Board, at func(x, y uint8) uint8, band uint8, sxs [3]uint8, baseValues Tile) bool {
+	for _, v := range MaskBits[baseValues] {
+		// rows is a bit mask of the rows (0-8) which can hold the value within each
+		// S column.
+		var rows [3]uint16
+		for si, x := range sxs {
+			for y := uint8(0); y < 9; y++ {
+				if y/3 == band {
+					continue
+				}
+				if b.Tiles[at(x, y)]&(1<<v) != 0 {
+					rows[si] |= 1 << y
+				}
+			}
+		}
+
+		// check if covered by 2 rows, or a column & a row, or 2 columns
+		isCovered := len(MaskBits[rows[0]|rows[1]|rows[2]]) <= 2
+		for si := range rows {
+			// column si, plus either a row or the remaining column
+			others := [2]uint16{rows[(si+1)%3], rows[(si+2)%3]}
+			if len(MaskBits[others[0]|others[1]]) <= 1 || others[0] == 0 || others[1] == 0 {
+				isCovered = true
+			}
+		}
+		if !isCovered {
+			return false
+		}
+	}
+	return true
+}
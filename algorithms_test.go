@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAlgoKnownValueElimination(t *testing.T) {
@@ -80,6 +82,33 @@ _ _ _ _ _ _ _ _ _
 	}
 }
 
+func TestAlgoOnlyRegion(t *testing.T) {
+	b := NewBoard()
+	// remove the value 1 from all tiles in row 0 outside of region 0
+	for x := uint8(3); x < 9; x++ {
+		b.set(xyToIndex(x, 0), ^Tile(1))
+	}
+
+	a := algoOnlyRegion{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range RegionIndices[0][3:] {
+		if b.Tiles[ti]&1<<0 != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], 1<<0)
+		}
+	}
+	for _, ti := range RegionIndices[0][:3] {
+		if b.Tiles[ti] != tAny {
+			t.Errorf("b.Tiles[%d] is %09b, expected %09b", ti, b.Tiles[ti], tAny)
+		}
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
 func numsTile(nums ...uint8) Tile {
 	tv := Tile(0)
 	for _, n := range nums {
@@ -136,3 +165,831 @@ func TestAlgoHiddenSubset(t *testing.T) {
 		}
 	}
 }
+
+func TestAlgoNakedSubset_triple(t *testing.T) {
+	b := NewBoard()
+	// a naked triple in row 0 where no 2 tiles have the same possibilities
+	b.set(0, numsTile(1, 2))
+	b.set(4, numsTile(2, 3))
+	b.set(8, numsTile(1, 3))
+
+	a := algoNakedSubset{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range RowIndices[0] {
+		if ti == 0 || ti == 4 || ti == 8 {
+			continue
+		}
+		if b.Tiles[ti]&numsTile(1, 2, 3) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1, 2, 3))
+		}
+	}
+	if b.Tiles[4] != numsTile(2, 3) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", 4, b.Tiles[4], numsTile(2, 3))
+	}
+}
+
+func TestAlgoHiddenSubset_triple(t *testing.T) {
+	b := NewBoard()
+	// in row 0, 1 is only possible in tiles 0 & 1, 2 in tiles 1 & 2, and 3 in
+	// tiles 0 & 2
+	b.set(0, ^numsTile(2))
+	b.set(1, ^numsTile(3))
+	b.set(2, ^numsTile(1))
+	for ti := uint8(3); ti < 9; ti++ {
+		b.set(ti, ^numsTile(1, 2, 3))
+	}
+
+	a := algoHiddenSubset{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for ti, expected := range []Tile{numsTile(1, 3), numsTile(1, 2), numsTile(2, 3)} {
+		if b.Tiles[ti] != expected {
+			t.Errorf("b.Tiles[%d] is %09b, expected %09b", ti, b.Tiles[ti], expected)
+		}
+	}
+}
+
+func TestAlgoFish(t *testing.T) {
+	b := NewBoard()
+	// build a swordfish on the value 5 using columns 1, 4 & 7 as the base, and
+	// rows 0, 3 & 6 as the cover.
+	baseRows := map[uint8][]uint8{
+		1: {0, 3},
+		4: {3, 6},
+		7: {0, 6},
+	}
+	for x, ys := range baseRows {
+	RowLoop:
+		for y := uint8(0); y < 9; y++ {
+			for _, by := range ys {
+				if y == by {
+					continue RowLoop
+				}
+			}
+			b.set(xyToIndex(x, y), ^numsTile(5))
+		}
+	}
+
+	a := algoFish{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, y := range []uint8{0, 3, 6} {
+		for x := uint8(0); x < 9; x++ {
+			ti := xyToIndex(x, y)
+			if _, ok := baseRows[x]; ok {
+				continue
+			}
+			if b.Tiles[ti]&numsTile(5) != 0 {
+				t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(5))
+			}
+		}
+	}
+	if b.Tiles[xyToIndex(1, 0)]&numsTile(5) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 0), b.Tiles[xyToIndex(1, 0)], numsTile(5))
+	}
+}
+
+func TestAlgoFinnedFish(t *testing.T) {
+	b := NewBoard()
+	// build a finned x-wing on the value 3 using rows 1 & 4 as the base, columns
+	// 1 & 7 as the cover, and a fin at x=8,y=4.
+	for x := uint8(0); x < 9; x++ {
+		if x != 1 && x != 7 {
+			b.set(xyToIndex(x, 1), ^numsTile(3))
+		}
+		if x != 1 && x != 7 && x != 8 {
+			b.set(xyToIndex(x, 4), ^numsTile(3))
+		}
+	}
+
+	a := algoFinnedFish{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// the tiles in column 7 which are in the fin's region
+	for _, ti := range []uint8{xyToIndex(7, 3), xyToIndex(7, 5)} {
+		if b.Tiles[ti]&numsTile(3) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(3))
+		}
+	}
+	// tiles in the cover lines, but outside the fin's region
+	for _, ti := range []uint8{xyToIndex(7, 0), xyToIndex(7, 8), xyToIndex(1, 3), xyToIndex(1, 5)} {
+		if b.Tiles[ti]&numsTile(3) == 0 {
+			t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", ti, b.Tiles[ti], numsTile(3))
+		}
+	}
+}
+
+func TestAlgoXYWing(t *testing.T) {
+	b := NewBoard()
+	// pivot at 0,0 [1,2], pincers at 4,0 [1,3] and 0,4 [2,3]
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 3))
+	b.set(xyToIndex(0, 4), numsTile(2, 3))
+
+	a := algoXYWing{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// the tile seeing both pincers
+	if b.Tiles[xyToIndex(4, 4)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], numsTile(3))
+	}
+	// sees only one pincer
+	if b.Tiles[xyToIndex(1, 0)]&numsTile(3) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 0), b.Tiles[xyToIndex(1, 0)], numsTile(3))
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
+func TestAlgoXYZWing(t *testing.T) {
+	b := NewBoard()
+	// pivot at 1,1 [1,2,3], pincers at 0,0 [1,3] and 7,1 [2,3]
+	b.set(xyToIndex(1, 1), numsTile(1, 2, 3))
+	b.set(xyToIndex(0, 0), numsTile(1, 3))
+	b.set(xyToIndex(7, 1), numsTile(2, 3))
+
+	a := algoXYZWing{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// tiles seeing the pivot and both pincers
+	for _, ti := range []uint8{xyToIndex(0, 1), xyToIndex(2, 1)} {
+		if b.Tiles[ti]&numsTile(3) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(3))
+		}
+	}
+	// sees both pincers, but not the pivot
+	if b.Tiles[xyToIndex(7, 0)]&numsTile(3) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(7, 0), b.Tiles[xyToIndex(7, 0)], numsTile(3))
+	}
+}
+
+func TestStrongLinks(t *testing.T) {
+	b := NewBoard()
+	// leave 7 possible only in tiles 0 & 1 in row 0 (and region 0), and in tiles 0
+	// & 27 of column 0.
+	for x := uint8(2); x < 9; x++ {
+		b.set(xyToIndex(x, 0), ^numsTile(7))
+	}
+	for _, ti := range []uint8{9, 10, 11, 18, 19, 20, 36, 45, 54, 63, 72} {
+		b.set(ti, ^numsTile(7))
+	}
+
+	links := strongLinks(&b, numsTile(7))
+	expected := []strongLink{{0, 1}, {0, 27}}
+	if fmt.Sprintf("%v", links) != fmt.Sprintf("%v", expected) {
+		t.Errorf("strongLinks is %v, expected %v", links, expected)
+	}
+}
+
+func TestAlgoWWing(t *testing.T) {
+	b := NewBoard()
+	// [1,2] tiles at 0,0 and 8,4, with a strong link on 1 in column 4 between 4,0
+	// and 4,4.
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(8, 4), numsTile(1, 2))
+	for y := uint8(0); y < 9; y++ {
+		if y != 0 && y != 4 {
+			b.set(xyToIndex(4, y), ^numsTile(1))
+		}
+	}
+
+	a := algoWWing{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// tiles seeing both wings
+	for _, ti := range []uint8{xyToIndex(0, 4), xyToIndex(8, 0)} {
+		if b.Tiles[ti]&numsTile(2) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(2))
+		}
+	}
+	if b.Tiles[xyToIndex(1, 4)]&numsTile(2) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 4), b.Tiles[xyToIndex(1, 4)], numsTile(2))
+	}
+}
+
+func TestAlgoWWing_changes(t *testing.T) {
+	b := NewBoard()
+	// the same as TestAlgoWWing, but the strong link only forms after the wings
+	// have been evaluated.
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(8, 4), numsTile(1, 2))
+	for y := uint8(0); y < 8; y++ {
+		if y != 0 && y != 4 {
+			b.set(xyToIndex(4, y), ^numsTile(1))
+		}
+	}
+
+	a := algoWWing{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+	if b.Tiles[xyToIndex(8, 0)]&numsTile(2) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(8, 0), b.Tiles[xyToIndex(8, 0)], numsTile(2))
+	}
+
+	// only the tile completing the strong link has changed
+	b.clearChanges()
+	b.set(xyToIndex(4, 8), ^numsTile(1))
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+	if b.Tiles[xyToIndex(8, 0)]&numsTile(2) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(8, 0), b.Tiles[xyToIndex(8, 0)], numsTile(2))
+	}
+
+	// nothing has changed, so nothing is evaluated
+	b.Tiles[xyToIndex(0, 4)] = tAny
+	if a.EvaluateChanges(&b, nil) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+	if b.Tiles[xyToIndex(0, 4)] != tAny {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 4), b.Tiles[xyToIndex(0, 4)], tAny)
+	}
+}
+
+func TestAlgoColoring(t *testing.T) {
+	b := NewBoard()
+	// Build a chain of strong links on the value 1:
+	// 0,0 -(row 0)- 4,0 -(region 1)- 5,2 -(column 5)- 5,6
+	for x := uint8(0); x < 9; x++ {
+		if x != 0 && x != 4 {
+			b.set(xyToIndex(x, 0), ^numsTile(1))
+		}
+	}
+	for _, ti := range RegionIndices[1] {
+		if ti != xyToIndex(4, 0) && ti != xyToIndex(5, 2) {
+			b.set(ti, ^numsTile(1))
+		}
+	}
+	for y := uint8(0); y < 9; y++ {
+		if y != 2 && y != 6 {
+			b.set(xyToIndex(5, y), ^numsTile(1))
+		}
+	}
+
+	a := algoColoring{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// 0,6 sees both ends of the chain, which are opposite colors
+	if b.Tiles[xyToIndex(0, 6)]&numsTile(1) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(0, 6), b.Tiles[xyToIndex(0, 6)], numsTile(1))
+	}
+	// 1,6 only sees one end
+	if b.Tiles[xyToIndex(1, 6)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 6), b.Tiles[xyToIndex(1, 6)], numsTile(1))
+	}
+}
+
+func TestAlgoXCycles(t *testing.T) {
+	b := NewBoard()
+	// Build a loop on the value 1, with 2 weak links at 3,0:
+	// 3,0 -(row 0)- 1,0 =(column 1)= 1,5 -(row 5)- 4,5 =(column 4)= 4,1 -(region 1)- 3,0
+	for y := uint8(0); y < 9; y++ {
+		if y != 0 && y != 5 {
+			b.set(xyToIndex(1, y), ^numsTile(1))
+		}
+		if y != 1 && y != 5 {
+			b.set(xyToIndex(4, y), ^numsTile(1))
+		}
+	}
+
+	a := algoXCycles{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range []uint8{xyToIndex(3, 0), xyToIndex(5, 0), xyToIndex(0, 1), xyToIndex(2, 1)} {
+		if b.Tiles[ti]&numsTile(1) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1))
+		}
+	}
+	if b.Tiles[xyToIndex(6, 0)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(6, 0), b.Tiles[xyToIndex(6, 0)], numsTile(1))
+	}
+}
+
+func TestAlgoXYChain(t *testing.T) {
+	b := NewBoard()
+	// 0,0 [1,2] - 4,0 [2,3] - 4,4 [3,4] - 8,4 [4,1]
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(2, 3))
+	b.set(xyToIndex(4, 4), numsTile(3, 4))
+	b.set(xyToIndex(8, 4), numsTile(1, 4))
+
+	a := algoXYChain{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range []uint8{xyToIndex(0, 4), xyToIndex(8, 0)} {
+		if b.Tiles[ti]&numsTile(1) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1))
+		}
+	}
+	if b.Tiles[xyToIndex(1, 4)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 4), b.Tiles[xyToIndex(1, 4)], numsTile(1))
+	}
+}
+
+func TestAlgoUniqueRectangle(t *testing.T) {
+	b := NewBoard()
+	// type 1: 3 corners of [1,2], and a 4th corner with extra values
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 1), numsTile(1, 2))
+	b.set(xyToIndex(4, 1), numsTile(1, 2, 5, 6))
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(4, 1)] != numsTile(5, 6) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 1), b.Tiles[xyToIndex(4, 1)], numsTile(5, 6))
+	}
+}
+
+func TestAlgoUniqueRectangle_type4(t *testing.T) {
+	b := NewBoard()
+	// floor of [1,2] in row 0, roof in row 1 where 1 is only possible in the roof
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 1), numsTile(1, 2, 5))
+	b.set(xyToIndex(4, 1), numsTile(1, 2, 6, 7))
+	for x := uint8(0); x < 9; x++ {
+		if x != 0 && x != 4 {
+			b.set(xyToIndex(x, 1), ^numsTile(1))
+		}
+	}
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(0, 1)] != numsTile(1, 5) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 1), b.Tiles[xyToIndex(0, 1)], numsTile(1, 5))
+	}
+	if b.Tiles[xyToIndex(4, 1)] != numsTile(1, 6, 7) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 1), b.Tiles[xyToIndex(4, 1)], numsTile(1, 6, 7))
+	}
+}
+
+func TestAlgoUniqueRectangle_type2(t *testing.T) {
+	b := NewBoard()
+	// floor of [1,2] in row 0, roof of [1,2,5] in row 1
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 1), numsTile(1, 2, 5))
+	b.set(xyToIndex(4, 1), numsTile(1, 2, 5))
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// one of the roof tiles must be 5, so the rest of row 1 can't be
+	if b.Tiles[xyToIndex(8, 1)]&numsTile(5) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(8, 1), b.Tiles[xyToIndex(8, 1)], numsTile(5))
+	}
+	if b.Tiles[xyToIndex(0, 2)]&numsTile(5) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(0, 2), b.Tiles[xyToIndex(0, 2)], numsTile(5))
+	}
+}
+
+func TestAlgoUniqueRectangle_type3(t *testing.T) {
+	b := NewBoard()
+	// floor of [1,2] in row 0, roof of [1,2,5] & [1,2,6] in row 1, which form a
+	// naked pair with [5,6] in row 1
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(3, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 1), numsTile(1, 2, 5))
+	b.set(xyToIndex(3, 1), numsTile(1, 2, 6))
+	b.set(xyToIndex(8, 1), numsTile(5, 6))
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(5, 1)] != ^numsTile(5, 6)&tAny {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(5, 1), b.Tiles[xyToIndex(5, 1)], ^numsTile(5, 6)&tAny)
+	}
+	if b.Tiles[xyToIndex(8, 1)] != numsTile(5, 6) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(8, 1), b.Tiles[xyToIndex(8, 1)], numsTile(5, 6))
+	}
+}
+
+func TestAlgoUniqueRectangle_type5(t *testing.T) {
+	b := NewBoard()
+	// a single floor tile of [1,2], and 3 roof tiles of [1,2,5]
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2, 5))
+	b.set(xyToIndex(0, 1), numsTile(1, 2, 5))
+	b.set(xyToIndex(4, 1), numsTile(1, 2, 5))
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// one of the roof tiles must be 5, so tiles which see all of them can't be
+	if b.Tiles[xyToIndex(3, 1)]&numsTile(5) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(3, 1), b.Tiles[xyToIndex(3, 1)], numsTile(5))
+	}
+	// sees only 2 of the roof tiles
+	if b.Tiles[xyToIndex(8, 1)]&numsTile(5) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(8, 1), b.Tiles[xyToIndex(8, 1)], numsTile(5))
+	}
+}
+
+func TestAlgoUniqueRectangle_type6(t *testing.T) {
+	b := NewBoard()
+	// floor of [1,2] on opposite corners, and 1 is only possible within the
+	// corners in rows 0 & 1
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 1), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2, 5))
+	b.set(xyToIndex(0, 1), numsTile(1, 2, 6))
+	for x := uint8(0); x < 9; x++ {
+		if x != 0 && x != 4 {
+			b.set(xyToIndex(x, 0), ^numsTile(1))
+			b.set(xyToIndex(x, 1), ^numsTile(1))
+		}
+	}
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(4, 0)] != numsTile(2, 5) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 0), b.Tiles[xyToIndex(4, 0)], numsTile(2, 5))
+	}
+	if b.Tiles[xyToIndex(0, 1)] != numsTile(2, 6) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 1), b.Tiles[xyToIndex(0, 1)], numsTile(2, 6))
+	}
+}
+
+func TestAlgoUniqueRectangle_fourRegions(t *testing.T) {
+	b := NewBoard()
+	// the type 1 pattern, but spanning 4 regions, so it isn't a deadly pattern
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 4), numsTile(1, 2))
+	b.set(xyToIndex(4, 4), numsTile(1, 2, 5, 6))
+
+	a := algoUniqueRectangle{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(4, 4)] != numsTile(1, 2, 5, 6) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], numsTile(1, 2, 5, 6))
+	}
+}
+
+func TestAlgoBUG(t *testing.T) {
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(`1 8 7 3 6 9 4 5 2
+5 4 6 2 8 7 9 3 1
+9 3 2 1 5 4 8 6 7
+2 1 9 5 3 8 7 4 6
+4 6 5 9 7 2 3 1 8
+3 7 8 6 4 1 2 9 5
+7 5 4 8 9 6 1 2 3
+8 2 3 4 1 5 6 7 9
+6 9 1 7 2 3 5 8 4
+`))
+	// every unknown tile is [1,2] except for one with [1,2,5]
+	b.Tiles[xyToIndex(0, 0)] = numsTile(1, 2)
+	b.Tiles[xyToIndex(4, 0)] = numsTile(1, 2)
+	b.Tiles[xyToIndex(0, 1)] = numsTile(1, 2)
+	b.Tiles[xyToIndex(4, 1)] = numsTile(1, 2, 5)
+
+	a := algoBUG{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(4, 1)] != numsTile(5) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 1), b.Tiles[xyToIndex(4, 1)], numsTile(5))
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
+func TestAlgoTurbotFish(t *testing.T) {
+	b := NewBoard()
+	// Build a skyscraper on the value 1 with strong links in columns 1 & 4:
+	// 1,0 = 1,5 and 4,1 = 4,5
+	for y := uint8(0); y < 9; y++ {
+		if y != 0 && y != 5 {
+			b.set(xyToIndex(1, y), ^numsTile(1))
+		}
+		if y != 1 && y != 5 {
+			b.set(xyToIndex(4, y), ^numsTile(1))
+		}
+	}
+
+	a := algoTurbotFish{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range []uint8{xyToIndex(3, 0), xyToIndex(5, 0), xyToIndex(0, 1), xyToIndex(2, 1)} {
+		if b.Tiles[ti]&numsTile(1) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1))
+		}
+	}
+	if b.Tiles[xyToIndex(6, 0)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(6, 0), b.Tiles[xyToIndex(6, 0)], numsTile(1))
+	}
+}
+
+func TestAlgoTurbotFish_emptyRectangle(t *testing.T) {
+	b := NewBoard()
+	// the possible tiles for 1 in region 0 form a cross on row 0 & column 0
+	for _, ti := range []uint8{xyToIndex(1, 1), xyToIndex(2, 1), xyToIndex(1, 2), xyToIndex(2, 2)} {
+		b.set(ti, ^numsTile(1))
+	}
+	// strong link in column 5 between 5,0 and 5,6
+	for y := uint8(0); y < 9; y++ {
+		if y != 0 && y != 6 {
+			b.set(xyToIndex(5, y), ^numsTile(1))
+		}
+	}
+
+	a := algoTurbotFish{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(0, 6)]&numsTile(1) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(0, 6), b.Tiles[xyToIndex(0, 6)], numsTile(1))
+	}
+	if b.Tiles[xyToIndex(0, 7)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(0, 7), b.Tiles[xyToIndex(0, 7)], numsTile(1))
+	}
+}
+
+func TestAlgoALS(t *testing.T) {
+	b := NewBoard()
+	// ALS A at 0,1 [1,2], ALS B at 4,1 [1,3] & 5,2 [2,3], restricted common 1
+	b.set(xyToIndex(0, 1), numsTile(1, 2))
+	b.set(xyToIndex(4, 1), numsTile(1, 3))
+	b.set(xyToIndex(5, 2), numsTile(2, 3))
+
+	a := algoALS{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// tiles seeing all the 2s of both sets
+	for _, ti := range []uint8{xyToIndex(3, 1), xyToIndex(5, 1), xyToIndex(1, 2), xyToIndex(2, 2)} {
+		if b.Tiles[ti]&numsTile(2) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(2))
+		}
+	}
+	// sees only the 2 of set A
+	if b.Tiles[xyToIndex(6, 1)]&numsTile(2) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(6, 1), b.Tiles[xyToIndex(6, 1)], numsTile(2))
+	}
+	if a.AlgoStats.Hits == 0 {
+		t.Errorf("a.AlgoStats.Hits is 0, expected >0")
+	}
+}
+
+func TestAlgoALS_xyWing(t *testing.T) {
+	b := NewBoard()
+	// ALS A at 0,0 [1,3], ALS C at 4,0 [1,2], ALS B at 4,4 [2,3]
+	b.set(xyToIndex(0, 0), numsTile(1, 3))
+	b.set(xyToIndex(4, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 4), numsTile(2, 3))
+
+	a := algoALS{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// the tile seeing the 3s of both A & B
+	if b.Tiles[xyToIndex(0, 4)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(0, 4), b.Tiles[xyToIndex(0, 4)], numsTile(3))
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
+func TestAlgoSueDeCoq(t *testing.T) {
+	b := NewBoard()
+	// intersection tiles 0,0 & 1,0 [1,2,3,4], row tile 5,0 [1,2], region tile 0,1
+	// [3,4]
+	b.set(xyToIndex(0, 0), numsTile(1, 2, 3, 4))
+	b.set(xyToIndex(1, 0), numsTile(1, 2, 3, 4))
+	b.set(xyToIndex(2, 0), numsTile(1, 2, 3, 4, 5, 6))
+	b.set(xyToIndex(5, 0), numsTile(1, 2))
+	b.set(xyToIndex(0, 1), numsTile(3, 4))
+
+	a := algoSueDeCoq{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// in both the row & region
+	if b.Tiles[xyToIndex(2, 0)] != numsTile(5, 6) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(2, 0), b.Tiles[xyToIndex(2, 0)], numsTile(5, 6))
+	}
+	// in the row
+	if b.Tiles[xyToIndex(8, 0)] != ^numsTile(1, 2)&tAny {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(8, 0), b.Tiles[xyToIndex(8, 0)], ^numsTile(1, 2)&tAny)
+	}
+	// in the region
+	if b.Tiles[xyToIndex(2, 2)] != ^numsTile(3, 4)&tAny {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(2, 2), b.Tiles[xyToIndex(2, 2)], ^numsTile(3, 4)&tAny)
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
+func TestAlgoDeathBlossom(t *testing.T) {
+	b := NewBoard()
+	// stem at 4,4 [1,2], petals at 4,0 [1,3] and 0,4 [2,3]
+	b.set(xyToIndex(4, 4), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 3))
+	b.set(xyToIndex(0, 4), numsTile(2, 3))
+
+	a := algoDeathBlossom{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// the tile seeing the 3s of both petals
+	if b.Tiles[xyToIndex(0, 0)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(0, 0), b.Tiles[xyToIndex(0, 0)], numsTile(3))
+	}
+	// sees only one petal
+	if b.Tiles[xyToIndex(1, 0)]&numsTile(3) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(1, 0), b.Tiles[xyToIndex(1, 0)], numsTile(3))
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
+
+func TestAlgoDeathBlossom_budget(t *testing.T) {
+	b := NewBoard()
+	// stem at 0,0 [1,2], petals at 4,0 [1,3] and 0,4 [2,3]
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 3))
+	b.set(xyToIndex(0, 4), numsTile(2, 3))
+
+	// the budget runs out part way through the first stem, so the search has to
+	// resume within it.
+	a := algoDeathBlossom{Budget: time.Nanosecond}
+	changes := b.changes()
+	for i := 0; i < 5 && b.Tiles[xyToIndex(4, 4)]&numsTile(3) != 0; i++ {
+		if a.EvaluateChanges(&b, changes) != true {
+			t.Errorf("EvaluateChanges is false, expected true")
+		}
+	}
+
+	// the tile seeing the 3s of both petals
+	if b.Tiles[xyToIndex(4, 4)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], numsTile(3))
+	}
+}
+
+func TestAlgoAlignedPairExclusion(t *testing.T) {
+	b := NewBoard()
+	// pair 0,0 [1,2] & 4,0 [1,2,3,4], with bi-value tiles 6,0 [1,3] & 8,0 [2,3]
+	// seeing both. 4,0 can't be 3, as 0,0 would leave no value for one of them.
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 2, 3, 4))
+	b.set(xyToIndex(6, 0), numsTile(1, 3))
+	b.set(xyToIndex(8, 0), numsTile(2, 3))
+
+	a := algoAlignedPairExclusion{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(4, 0)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(4, 0), b.Tiles[xyToIndex(4, 0)], numsTile(3))
+	}
+	if b.Tiles[xyToIndex(0, 0)] != numsTile(1, 2) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 0), b.Tiles[xyToIndex(0, 0)], numsTile(1, 2))
+	}
+	if a.AlgoStats.Hits == 0 {
+		t.Errorf("a.AlgoStats.Hits is 0, expected >0")
+	}
+}
+
+func TestPatternTemplates(t *testing.T) {
+	if len(PatternTemplates) != 46656 {
+		t.Errorf("len(PatternTemplates) is %d, expected %d", len(PatternTemplates), 46656)
+	}
+	seen := map[tileSet]bool{}
+	for _, ts := range PatternTemplates {
+		if seen[ts] {
+			t.Fatalf("template %v is duplicated", ts.indices())
+		}
+		seen[ts] = true
+
+		tis := ts.indices()
+		if len(tis) != 9 {
+			t.Fatalf("template %v has %d tiles, expected %d", tis, len(tis), 9)
+		}
+		// one tile in each row, column, & region
+		var rows, columns, regions uint16
+		for _, ti := range tis {
+			x, y := indexToXY(ti)
+			rows |= 1 << y
+			columns |= 1 << x
+			regions |= 1 << (y/3*3 + x/3)
+		}
+		if rows != 0x1ff || columns != 0x1ff || regions != 0x1ff {
+			t.Fatalf("template %v does not have one tile in each row, column, & region", tis)
+		}
+	}
+}
+
+func TestAlgoPatternOverlay(t *testing.T) {
+	b := NewBoard()
+	// 1 is only possible within region 0 in row 0, and only at 0,8 in column 0
+	for x := uint8(3); x < 9; x++ {
+		b.set(xyToIndex(x, 0), ^numsTile(1))
+	}
+	for y := uint8(0); y < 8; y++ {
+		b.set(xyToIndex(0, y), ^numsTile(1))
+	}
+
+	a := algoPatternOverlay{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// in every template
+	if b.Tiles[xyToIndex(0, 8)] != numsTile(1) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 8), b.Tiles[xyToIndex(0, 8)], numsTile(1))
+	}
+	// in no template
+	for _, ti := range []uint8{xyToIndex(1, 1), xyToIndex(2, 2), xyToIndex(5, 8)} {
+		if b.Tiles[ti]&numsTile(1) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1))
+		}
+	}
+	if b.Tiles[xyToIndex(4, 4)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], numsTile(1))
+	}
+}
+
+func TestAlgoExocet(t *testing.T) {
+	b := NewBoard()
+	// base tiles 0,0 & 1,0 [1,2,3], targets 3,1 & 6,2, with mirrors 3,2 & 6,1, and
+	// S columns 2, 3, & 6.
+	b.set(xyToIndex(0, 0), numsTile(1, 2, 3))
+	b.set(xyToIndex(1, 0), numsTile(1, 2, 3))
+	b.set(xyToIndex(3, 2), ^numsTile(1, 2, 3))
+	b.set(xyToIndex(6, 1), ^numsTile(1, 2, 3))
+	// outside the band, the S columns can only hold the base values in rows 3 & 4
+	for _, x := range []uint8{2, 3, 6} {
+		for y := uint8(5); y < 9; y++ {
+			b.set(xyToIndex(x, y), ^numsTile(1, 2, 3))
+		}
+	}
+
+	a := algoExocet{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	for _, ti := range []uint8{xyToIndex(3, 1), xyToIndex(6, 2)} {
+		if b.Tiles[ti] != numsTile(1, 2, 3) {
+			t.Errorf("b.Tiles[%d] is %09b, expected %09b", ti, b.Tiles[ti], numsTile(1, 2, 3))
+		}
+	}
+	if a.AlgoStats.Hits != 1 {
+		t.Errorf("a.AlgoStats.Hits is %d, expected %d", a.AlgoStats.Hits, 1)
+	}
+}
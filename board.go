@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/bits"
 	"time"
 )
 
@@ -82,6 +83,101 @@ var ColumnIndices [9][9]uint8 = func() (idcs [9][9]uint8) {
 	return
 }()
 
+// NeighborSetIndices is a pre-calculated lookup table for obtaining the tile
+// indices within a board for every neighbor set (rows, then columns, then
+// regions). It is the same as RowIndices, ColumnIndices, & RegionIndices
+// concatenated together.
+var NeighborSetIndices [9 * 3][9]uint8 = func() (idcs [9 * 3][9]uint8) {
+	copy(idcs[0:9], RowIndices[:])
+	copy(idcs[9:18], ColumnIndices[:])
+	copy(idcs[18:27], RegionIndices[:])
+	return
+}()
+
+// PeerIndices is a pre-calculated lookup table for obtaining the indices of all
+// the tiles which share a row, column, or region with the tile at the given
+// index (not including the tile itself).
+var PeerIndices [9 * 9][20]uint8 = func() (idcs [9 * 9][20]uint8) {
+	for ti := range idcs {
+		i := 0
+		for nti := uint8(0); nti < 9*9; nti++ {
+			if tilesSee(uint8(ti), nti) {
+				idcs[ti][i] = nti
+				i++
+			}
+		}
+	}
+	return
+}()
+
+// tileSet is a set of tile indices, as a bit mask. Bit N of word 0 is tile N,
+// and bit N of word 1 is tile 64+N.
+type tileSet [2]uint64
+
+// add adds the tile ti to the set.
+func (ts *tileSet) add(ti uint8) { ts[ti/64] |= 1 << (ti % 64) }
+
+// has indicates whether the tile ti is in the set.
+func (ts tileSet) has(ti uint8) bool { return ts[ti/64]&(1<<(ti%64)) != 0 }
+
+// and returns the intersection of the 2 sets.
+func (ts tileSet) and(ts2 tileSet) tileSet { return tileSet{ts[0] & ts2[0], ts[1] & ts2[1]} }
+
+// or returns the union of the 2 sets.
+func (ts tileSet) or(ts2 tileSet) tileSet { return tileSet{ts[0] | ts2[0], ts[1] | ts2[1]} }
+
+// andNot returns the tiles in ts which are not in ts2.
+func (ts tileSet) andNot(ts2 tileSet) tileSet { return tileSet{ts[0] &^ ts2[0], ts[1] &^ ts2[1]} }
+
+// empty indicates whether the set has no tiles.
+func (ts tileSet) empty() bool { return ts[0] == 0 && ts[1] == 0 }
+
+// pop removes the lowest tile index from the set, and returns it. The set must
+// not be empty.
+func (ts *tileSet) pop() uint8 {
+	if ts[0] != 0 {
+		ti := uint8(bits.TrailingZeros64(ts[0]))
+		ts[0] &= ts[0] - 1
+		return ti
+	}
+	ti := uint8(64 + bits.TrailingZeros64(ts[1]))
+	ts[1] &= ts[1] - 1
+	return ti
+}
+
+// indices returns the tile indices within the set, in ascending order.
+func (ts tileSet) indices() []uint8 {
+	var tis []uint8
+	for i, w := range ts {
+		for ; w != 0; w &= w - 1 {
+			tis = append(tis, uint8(i*64+bits.TrailingZeros64(w)))
+		}
+	}
+	return tis
+}
+
+// PeerSets is a pre-calculated lookup table of the same tiles as PeerIndices,
+// but as a tileSet.
+var PeerSets [9 * 9]tileSet = func() (sets [9 * 9]tileSet) {
+	for ti := range sets {
+		for _, nti := range PeerIndices[ti][:] {
+			sets[ti].add(nti)
+		}
+	}
+	return
+}()
+
+// tilesSee indicates whether the 2 tiles at the given indices are different
+// tiles which share a row, column, or region.
+func tilesSee(ti1, ti2 uint8) bool {
+	if ti1 == ti2 {
+		return false
+	}
+	x1, y1 := indexToXY(ti1)
+	x2, y2 := indexToXY(ti2)
+	return x1 == x2 || y1 == y2 || tileIndexToRegionIndex(ti1) == tileIndexToRegionIndex(ti2)
+}
+
 // MaskBits is a pre-calculated lookup table for converting a uint16
 // (values 0-511) into a slice indicating which bits are set.
 // E.G. `MaskBits[0b001000101] == []uint8{0,2,6}`
@@ -97,6 +193,32 @@ var MaskBits [512][]uint8 = func() (mbs [512][]uint8) {
 	return
 }()
 
+// PatternTemplates is a pre-calculated lookup table of every valid placement of
+// a single value on the board (one tile in each row, column, & region). There
+// are 46,656 of them.
+var PatternTemplates []tileSet = func() (templates []tileSet) {
+	templates = make([]tileSet, 0, 46656)
+	var placeRow func(y uint8, ts tileSet, columns, regions uint16)
+	placeRow = func(y uint8, ts tileSet, columns, regions uint16) {
+		if y == 9 {
+			templates = append(templates, ts)
+			return
+		}
+		for x := uint8(0); x < 9; x++ {
+			ti := xyToIndex(x, y)
+			rgnIdx := tileIndexToRegionIndex(ti)
+			if columns&(1<<x) != 0 || regions&(1<<rgnIdx) != 0 {
+				continue
+			}
+			ts2 := ts
+			ts2.add(ti)
+			placeRow(y+1, ts2, columns|1<<x, regions|1<<rgnIdx)
+		}
+	}
+	placeRow(0, tileSet{}, 0, 0)
+	return
+}()
+
 // Tile represents a sudoku tile, and the possible values it may hold.
 // The value is a 9-bit mask (uint16 with 7 bits unused), with bit 0 indicating
 // whether the tile can hold the digit 1, through bit 8 indicating whether the
@@ -143,6 +265,19 @@ func (t Tile) Num() uint8 {
 	return lookupTable[(uint32(t<<1)*0x077CB531)>>27]
 }
 
+// Engine is a solving engine.
+type Engine int
+
+const (
+	// EngineAlgorithms solves using Board.Algorithms, guessing when they can't
+	// make any further progress.
+	EngineAlgorithms Engine = iota
+	// EngineDLX solves using Knuth's Algorithm X, with dancing links. This is an
+	// exact cover search over the possible values of each tile, and does not use
+	// Board.Algorithms.
+	EngineDLX
+)
+
 // Board represents a sudoku board.
 type Board struct {
 	// Tiles holds a 9x9 grid of the tiles on the board.
@@ -152,6 +287,14 @@ type Board struct {
 
 	// Algorithms is a list of algorithms to use when solving the board.
 	Algorithms []Algorithm
+	// Engine is the solving engine used by Solve & EachSolution.
+	Engine Engine
+	// TileSelector chooses the tile to guess the value of, when the algorithms
+	// can't make any further progress. If nil, SelectMRV is used.
+	TileSelector TileSelector
+	// ValueOrderer orders the values to try when guessing. If nil, OrderNatural
+	// is used.
+	ValueOrderer ValueOrderer
 	// activeAlgorithmStats is a pointer the the AlgorithmStats for the algorithm
 	// which is currently running.
 	activeAlgorithmStats *AlgorithmStats
@@ -186,8 +329,21 @@ func NewBoard() Board {
 			&algoKnownValueElimination{},
 			&algoOnePossibleTile{},
 			&algoOnlyRow{},
+			&algoOnlyRegion{},
 			&algoNakedSubset{},
 			&algoHiddenSubset{},
+			&algoFish{},
+			&algoFinnedFish{},
+			&algoTurbotFish{},
+			&algoXYWing{},
+			&algoXYZWing{},
+			&algoWWing{},
+			&algoSueDeCoq{},
+			&algoAlignedPairExclusion{},
+			&algoColoring{},
+			&algoXCycles{},
+			&algoXYChain{},
+			&algoALS{},
 		},
 		guessStats: &AlgorithmStats{},
 	}
@@ -242,6 +398,18 @@ func (b *Board) set(ti uint8, t Tile) bool {
 	return true
 }
 
+// unset removes the possible values in t from the tile at the given index.
+// Returns whether the tile was changed, and whether the operation was
+// successful. The operation will be unsuccessful if it results in an invalid
+// board.
+func (b *Board) unset(ti uint8, t Tile) (changed, ok bool) {
+	t0 := b.Tiles[ti]
+	if !b.set(ti, ^t) {
+		return false, false
+	}
+	return b.Tiles[ti] != t0, true
+}
+
 // hasChanges indicates whether any tiles have been changed since the last call
 // to evaluateAlgorithms.
 func (b *Board) hasChanges() bool {
@@ -339,34 +507,15 @@ func (b *Board) guess() bool {
 		b.guessStats.Duration += time.Now().Sub(tStart)
 	}()
 
-	// first look for the tile with the least amount of possible values
-	uti := uint8(255)
-	utPossibilityCount := uint8(255)
-	for ti := range b.Tiles {
-		t := b.Tiles[ti]
-		if t.isKnown() {
-			continue
-		}
-		pc := uint8(len(MaskBits[t]))
-		if pc < utPossibilityCount {
-			uti = uint8(ti)
-			utPossibilityCount = pc
-			if pc == 2 {
-				// can't get less than 2 and still be unknown
-				break
-			}
-		}
-	}
+	uti := b.guessTile()
 	if uti == 255 {
 		// entire board already solved
 		return true
 	}
 
-	ut := b.Tiles[uti]
-
 	b0 := *b
 	// now try guessing a value
-	for _, v := range MaskBits[ut] {
+	for _, v := range b.guessValues(uti) {
 		t := Tile(1 << v)
 		b.guessStats.Duration += time.Now().Sub(tStart) // pause timer
 		if !b.Set(uti, t) {
@@ -401,6 +550,24 @@ func (b *Board) guess() bool {
 	return false
 }
 
+// guessTile returns the index of the tile to guess the value of, using the
+// board's TileSelector. Returns 255 if all tiles have a known value.
+func (b *Board) guessTile() uint8 {
+	if b.TileSelector == nil {
+		return SelectMRV(b)
+	}
+	return b.TileSelector(b)
+}
+
+// guessValues returns the possible values (0-8) of the tile at index ti, in the
+// order to guess them, using the board's ValueOrderer.
+func (b *Board) guessValues(ti uint8) []uint8 {
+	if b.ValueOrderer == nil {
+		return OrderNatural(b, ti)
+	}
+	return b.ValueOrderer(b, ti)
+}
+
 // Solved indicates whether all tiles have a known value.
 func (b *Board) Solved() bool {
 	for ti := uint8(0); ti < 9*9; ti++ {
@@ -412,15 +579,128 @@ func (b *Board) Solved() bool {
 	return true
 }
 
-// Solve tries to solve the board. If the board has no solution, false is
-// returned.
+// Solve tries to solve the board, using the board's Engine. If the board has no
+// solution, false is returned.
 func (b *Board) Solve() bool {
+	if b.Engine == EngineDLX {
+		return b.solveDLX()
+	}
 	if !b.evaluateAlgorithms() {
 		return false
 	}
 	return b.guess()
 }
 
+// SolutionClass is a classification of the number of solutions a board has.
+type SolutionClass int
+
+const (
+	// NoSolution indicates the board has no solution.
+	NoSolution SolutionClass = iota
+	// UniqueSolution indicates the board has exactly one solution.
+	UniqueSolution
+	// MultipleSolutions indicates the board has more than one solution.
+	MultipleSolutions
+)
+
+func (sc SolutionClass) String() string {
+	switch sc {
+	case NoSolution:
+		return "none"
+	case UniqueSolution:
+		return "unique"
+	case MultipleSolutions:
+		return "multiple"
+	}
+	return fmt.Sprintf("SolutionClass(%d)", int(sc))
+}
+
+// CountSolutions counts the number of solutions the board has, stopping once
+// limit solutions have been found. If limit is 0, all solutions are counted.
+// The board is not modified.
+// This always uses EngineDLX, as it is much faster at exhausting the search.
+func (b *Board) CountSolutions(limit int) int {
+	return b.eachSolutionDLX(limit, func(Board) bool { return true })
+}
+
+// EachSolution calls fn with each solution of the board, stopping once limit
+// solutions have been found, or fn returns false. If limit is 0, there is no
+// limit. Returns the number of solutions found.
+// The board's tiles are not modified, and its guesser statistics are not
+// updated.
+// With EngineAlgorithms, the board's algorithms are used, except for any of the
+// UniquenessAlgorithms, as they assume the board has a single solution. The same
+// algorithm instances are used, so their AlgorithmStats are updated.
+func (b *Board) EachSolution(limit int, fn func(solution Board) bool) int {
+	if b.Engine == EngineDLX {
+		return b.eachSolutionDLX(limit, fn)
+	}
+
+	b0 := *b
+	b0.Algorithms = withoutUniquenessAlgorithms(b.Algorithms)
+	b0.guessStats = &AlgorithmStats{}
+	if !b0.evaluateAlgorithms() {
+		return 0
+	}
+	count := 0
+	b0.eachSolution(limit, &count, fn)
+	return count
+}
+
+// withoutUniquenessAlgorithms returns a copy of algos, without any of the
+// UniquenessAlgorithms.
+func withoutUniquenessAlgorithms(algos []Algorithm) []Algorithm {
+	unique := map[string]bool{}
+	for _, a := range UniquenessAlgorithms() {
+		unique[a.Name()] = true
+	}
+	var filtered []Algorithm
+	for _, a := range algos {
+		if !unique[a.Name()] {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// eachSolution finds each solution by guessing each possible value of a tile in
+// turn, calling fn and incrementing count for each one.
+// Returns false once no more solutions should be found.
+func (b *Board) eachSolution(limit int, count *int, fn func(Board) bool) bool {
+	uti := b.guessTile()
+	if uti == 255 {
+		*count++
+		return fn(*b) && (limit == 0 || *count < limit)
+	}
+	for _, v := range b.guessValues(uti) {
+		b0 := *b
+		if !b0.Set(uti, 1<<v) {
+			continue
+		}
+		if !b0.eachSolution(limit, count, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClassifySolutions returns whether the board has no solution, a unique
+// solution, or multiple solutions.
+func (b *Board) ClassifySolutions() SolutionClass {
+	switch b.CountSolutions(2) {
+	case 0:
+		return NoSolution
+	case 1:
+		return UniqueSolution
+	}
+	return MultipleSolutions
+}
+
+// IsUnique indicates whether the board has exactly one solution.
+func (b *Board) IsUnique() bool {
+	return b.ClassifySolutions() == UniqueSolution
+}
+
 // ReadFrom reads the board from the provided io.Reader. In addition to read
 // errors, if the provided board is invalid, an error will be returned.
 //
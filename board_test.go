@@ -216,6 +216,107 @@ func TestGuess(t *testing.T) {
 	}
 }
 
+func TestCountSolutions(t *testing.T) {
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`))
+	b0 := b
+	if n := b.CountSolutions(0); n != 1 {
+		t.Errorf("b.CountSolutions(0) is %d, expected %d", n, 1)
+	}
+	if b.Tiles != b0.Tiles {
+		t.Errorf("b.CountSolutions(0) modified the board")
+	}
+	if sc := b.ClassifySolutions(); sc != UniqueSolution {
+		t.Errorf("b.ClassifySolutions() is %s, expected %s", sc, UniqueSolution)
+	}
+	if !b.IsUnique() {
+		t.Errorf("b.IsUnique() is false, expected true")
+	}
+
+	b = NewBoard()
+	if n := b.CountSolutions(5); n != 5 {
+		t.Errorf("b.CountSolutions(5) is %d, expected %d", n, 5)
+	}
+	if sc := b.ClassifySolutions(); sc != MultipleSolutions {
+		t.Errorf("b.ClassifySolutions() is %s, expected %s", sc, MultipleSolutions)
+	}
+
+	b = NewBoard()
+	b.set(0, numsTile(1))
+	b.set(1, numsTile(1))
+	if sc := b.ClassifySolutions(); sc != NoSolution {
+		t.Errorf("b.ClassifySolutions() is %s, expected %s", sc, NoSolution)
+	}
+}
+
+func TestEachSolution(t *testing.T) {
+	// a solved board with a deadly pattern of 5 & 9 removed, leaving 2 solutions
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(`1 8 7 3 6 9 4 5 2
+5 4 6 2 8 7 9 3 1
+9 3 2 1 5 4 8 6 7
+2 1 _ _ 3 8 7 4 6
+4 6 _ _ 7 2 3 1 8
+3 7 8 6 4 1 2 9 5
+7 5 4 8 9 6 1 2 3
+8 2 3 4 1 5 6 7 9
+6 9 1 7 2 3 5 8 4
+`))
+
+	var solutions []Board
+	n := b.EachSolution(0, func(solution Board) bool {
+		solutions = append(solutions, solution)
+		return true
+	})
+	if n != 2 || len(solutions) != 2 {
+		t.Fatalf("b.EachSolution(0) found %d (%d calls), expected %d", n, len(solutions), 2)
+	}
+	for _, solution := range solutions {
+		if !solution.Solved() {
+			t.Errorf("solution.Solved() is false, expected true")
+		}
+	}
+	if solutions[0].Tiles == solutions[1].Tiles {
+		t.Errorf("solutions are the same, expected them to differ")
+	}
+
+	if n := b.EachSolution(1, func(Board) bool { return true }); n != 1 {
+		t.Errorf("b.EachSolution(1) found %d, expected %d", n, 1)
+	}
+	if n := b.EachSolution(0, func(Board) bool { return false }); n != 1 {
+		t.Errorf("b.EachSolution(0) with fn returning false found %d, expected %d", n, 1)
+	}
+
+	// the board's own algorithms are used, except for the uniqueness algorithms
+	algCalls := 0
+	alg := testAlgorithm{func(b *Board, changes []uint8) bool {
+		algCalls++
+		return true
+	}}
+	uniqueAlgos := UniquenessAlgorithms()
+	b.Algorithms = append(append(NewBoard().Algorithms, alg), uniqueAlgos...)
+	if n := b.EachSolution(0, func(Board) bool { return true }); n != 2 {
+		t.Errorf("b.EachSolution(0) with custom algorithms found %d, expected %d", n, 2)
+	}
+	if algCalls == 0 {
+		t.Errorf("algCalls is 0, expected >0")
+	}
+	for _, a := range uniqueAlgos {
+		if a.Stats().Calls != 0 {
+			t.Errorf("%s was called %d times, expected %d", a.Name(), a.Stats().Calls, 0)
+		}
+	}
+}
+
 func TestReadFrom(t *testing.T) {
 	boardReader := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
 5 4 _ _ _ 7 _ 3 _
@@ -0,0 +1,540 @@
+package main
+
+// inferenceNode is a node within an inferenceGraph. It represents the
+// possibility of a value within a group of tiles. The node is "true" if any one
+// of its tiles holds the value, and "false" if none of them do.
+type inferenceNode struct {
+	v     Tile
+	tiles []uint8
+}
+
+// inferenceGraph is a graph of the inferences between the possible values of
+// the tiles on a board.
+// A strong link between 2 nodes means that at least one of them is true. A weak
+// link means that at most one of them is true.
+// Weak links are not stored, as there are far too many of them. Instead they're
+// found as needed from the nodes within each neighbor set.
+type inferenceGraph struct {
+	nodes  []inferenceNode
+	strong [][]int
+	// nodeSets holds the indices of the neighbor sets which contain all the tiles
+	// of each node.
+	nodeSets [][]uint8
+	// neighborSetNodes holds the nodes within each neighbor set, by value (0-8).
+	neighborSetNodes [9 * 3][9][]int
+	// tileNodes is a lookup table from a tile index and value (0-8) to the index
+	// of the node holding just that tile & value. -1 if there is no such node.
+	tileNodes [9 * 9][9]int
+
+	// seen & queue are working storage for implications.
+	seen  [][2]bool
+	queue []inferenceState
+}
+
+// inferenceState is a node, and whether it is true or false.
+type inferenceState struct {
+	n      int
+	isTrue bool
+}
+
+// BoxLineSegments is a pre-calculated lookup table of the tile indices for each
+// intersection of a region with a row (indices 0-26), and with a column
+// (indices 27-53).
+var BoxLineSegments [9 * 3 * 2][3]uint8 = func() (segs [9 * 3 * 2][3]uint8) {
+	for ri, rgnIndices := range RegionIndices {
+		for i := 0; i < 3; i++ {
+			segs[ri*3+i] = [3]uint8{rgnIndices[i*3], rgnIndices[i*3+1], rgnIndices[i*3+2]}
+			segs[27+ri*3+i] = [3]uint8{rgnIndices[i], rgnIndices[i+3], rgnIndices[i+6]}
+		}
+	}
+	return
+}()
+
+// neighborSetSegments is a pre-calculated lookup table of the indices of the
+// BoxLineSegments which lie within each neighbor set of NeighborSetIndices.
+var neighborSetSegments [9 * 3][]int = func() (nsSegs [9 * 3][]int) {
+	for nsi, idcs := range NeighborSetIndices {
+	SegmentLoop:
+		for si, seg := range BoxLineSegments {
+			for _, ti := range seg {
+				if !containsIndex(idcs[:], ti) {
+					continue SegmentLoop
+				}
+			}
+			nsSegs[nsi] = append(nsSegs[nsi], si)
+		}
+	}
+	return
+}()
+
+// containsIndex indicates whether the tile index ti is in the list idcs.
+func containsIndex(idcs []uint8, ti uint8) bool {
+	for _, i := range idcs {
+		if i == ti {
+			return true
+		}
+	}
+	return false
+}
+
+// newInferenceGraph builds the inference graph for the board. If grouped is
+// true, the graph includes group nodes (a value within the 2 or 3 tiles of an
+// intersection of a region with a row or column).
+func newInferenceGraph(b *Board, grouped bool) *inferenceGraph {
+	g := &inferenceGraph{}
+
+	// single tile nodes, and the links within each tile
+	for ti, t := range b.Tiles {
+		for v := range g.tileNodes[ti] {
+			g.tileNodes[ti][v] = -1
+		}
+		if t.isKnown() {
+			continue
+		}
+		vs := MaskBits[t]
+		for _, v := range vs {
+			g.tileNodes[ti][v] = g.addNode(Tile(1<<v), []uint8{uint8(ti)})
+		}
+		if len(vs) == 2 {
+			g.addStrongLink(g.tileNodes[ti][vs[0]], g.tileNodes[ti][vs[1]])
+		}
+	}
+
+	// group nodes
+	var segmentNodes [len(BoxLineSegments)][9]int
+	for si, seg := range BoxLineSegments {
+		for v := uint8(0); v < 9; v++ {
+			segmentNodes[si][v] = -1
+			if !grouped {
+				continue
+			}
+			var tiles []uint8
+			for _, ti := range seg {
+				if t := b.Tiles[ti]; t&(1<<v) != 0 && !t.isKnown() {
+					tiles = append(tiles, ti)
+				}
+			}
+			if len(tiles) >= 2 {
+				segmentNodes[si][v] = g.addNode(Tile(1<<v), tiles)
+			}
+		}
+	}
+
+	// the links within each neighbor set
+	type nsNode struct {
+		id int
+		// positions is a bit mask of the positions of the node's tiles within the
+		// neighbor set.
+		positions uint16
+	}
+	for nsi, idcs := range NeighborSetIndices {
+	ValueLoop:
+		for v := uint8(0); v < 9; v++ {
+			vt := Tile(1 << v)
+			var nsNodes []nsNode
+			var positions uint16
+			for i, ti := range idcs {
+				t := b.Tiles[ti]
+				if t == vt {
+					// this value has already been found
+					continue ValueLoop
+				}
+				if t&vt == 0 {
+					continue
+				}
+				positions |= 1 << uint16(i)
+				nsNodes = append(nsNodes, nsNode{g.tileNodes[ti][v], 1 << uint16(i)})
+				g.nodeSets[g.tileNodes[ti][v]] = append(g.nodeSets[g.tileNodes[ti][v]], uint8(nsi))
+			}
+			for _, si := range neighborSetSegments[nsi] {
+				id := segmentNodes[si][v]
+				if id < 0 {
+					continue
+				}
+				var nodePositions uint16
+				for i, ti := range idcs {
+					if containsIndex(g.nodes[id].tiles, ti) {
+						nodePositions |= 1 << uint16(i)
+					}
+				}
+				nsNodes = append(nsNodes, nsNode{id, nodePositions})
+				g.nodeSets[id] = append(g.nodeSets[id], uint8(nsi))
+			}
+
+			for i, n1 := range nsNodes {
+				g.neighborSetNodes[nsi][v] = append(g.neighborSetNodes[nsi][v], n1.id)
+				for _, n2 := range nsNodes[i+1:] {
+					if n1.positions&n2.positions == 0 && n1.positions|n2.positions == positions {
+						g.addStrongLink(n1.id, n2.id)
+					}
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// addNode adds a node to the graph and returns its index.
+func (g *inferenceGraph) addNode(v Tile, tiles []uint8) int {
+	g.nodes = append(g.nodes, inferenceNode{v: v, tiles: tiles})
+	g.strong = append(g.strong, nil)
+	g.nodeSets = append(g.nodeSets, nil)
+	return len(g.nodes) - 1
+}
+
+// addStrongLink adds a strong link between the 2 nodes.
+func (g *inferenceGraph) addStrongLink(n1, n2 int) {
+	g.strong[n1] = appendUniqueNode(g.strong[n1], n2)
+	g.strong[n2] = appendUniqueNode(g.strong[n2], n1)
+}
+
+// weakLinks calls fn with each node which is weakly linked to node n. A node may
+// be given more than once.
+// The weakly linked nodes are the other values within the same tile (for single
+// tile nodes), and the nodes with the same value in a neighbor set which contains
+// n, and which don't overlap with n.
+func (g *inferenceGraph) weakLinks(n int, fn func(int)) {
+	node := g.nodes[n]
+	if len(node.tiles) == 1 {
+		for _, nn := range g.tileNodes[node.tiles[0]] {
+			if nn >= 0 && nn != n {
+				fn(nn)
+			}
+		}
+	}
+	v := MaskBits[node.v][0]
+	for _, nsi := range g.nodeSets[n] {
+	NodeLoop:
+		for _, nn := range g.neighborSetNodes[nsi][v] {
+			for _, ti := range g.nodes[nn].tiles {
+				if containsIndex(node.tiles, ti) {
+					continue NodeLoop
+				}
+			}
+			fn(nn)
+		}
+	}
+}
+
+// appendUniqueNode appends the node index n to the list if not already present.
+func appendUniqueNode(ns []int, n int) []int {
+	for _, n0 := range ns {
+		if n0 == n {
+			return ns
+		}
+	}
+	return append(ns, n)
+}
+
+// implications finds all the nodes which are forced to be true if node n is
+// false, by following alternating strong & weak links from n.
+// The returned list is in the order the nodes were found.
+func (g *inferenceGraph) implications(n int) []int {
+	// seen tracks which nodes have been reached as true ([1]) & false ([0]).
+	if len(g.seen) != len(g.nodes) {
+		g.seen = make([][2]bool, len(g.nodes))
+	} else {
+		for i := range g.seen {
+			g.seen[i] = [2]bool{}
+		}
+	}
+	g.seen[n][0] = true
+	queue := append(g.queue[:0], inferenceState{n, false})
+	var trues []int
+	markFalse := func(nn int) {
+		if g.seen[nn][0] {
+			return
+		}
+		g.seen[nn][0] = true
+		queue = append(queue, inferenceState{nn, false})
+	}
+	for i := 0; i < len(queue); i++ {
+		s := queue[i]
+		if !s.isTrue {
+			// if this node is false, anything it's strongly linked to is true
+			for _, nn := range g.strong[s.n] {
+				if g.seen[nn][1] {
+					continue
+				}
+				g.seen[nn][1] = true
+				trues = append(trues, nn)
+				queue = append(queue, inferenceState{nn, true})
+			}
+			continue
+		}
+		// if this node is true, anything it's weakly linked to is false
+		g.weakLinks(s.n, markFalse)
+	}
+	g.queue = queue
+	return trues
+}
+
+// algoAIC finds Alternating Inference Chains, including chains with group
+// nodes.
+// An AIC is a chain of nodes (a value within a tile, or a group of tiles within
+// a region/line intersection) joined by alternating strong & weak links, which
+// starts & ends with a strong link. If the first node is false, the last node
+// must be true. So at least one of the 2 ends is true, which allows:
+//   - If both ends are the same value, any tile which sees all the tiles of both
+//     ends can't hold the value.
+//   - If the ends are different values in the same tile, the tile can't hold any
+//     other value.
+//   - If the ends are different values in tiles which see each other, neither
+//     tile can hold the value of the other end.
+//   - If the chain loops back to the first node, the first node must be true.
+//
+// It is not part of the default algorithms added by NewBoard. See
+// ExpensiveAlgorithms.
+//
+// https://www.sudokuwiki.org/Alternating_Inference_Chains
+// https://www.sudokuwiki.org/Grouped_X_Cycles
+type algoAIC struct {
+	AlgoStats AlgorithmStats
+}
+
+func (a algoAIC) Name() string { return "algoAIC" }
+
+func (a *algoAIC) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoAIC) EvaluateChanges(b *Board, changes []uint8) bool {
+	g := newInferenceGraph(b, true)
+
+	// Note that the graph isn't updated as we make changes. This is fine since a
+	// link only becomes invalid when one of its nodes is proven false, and
+	// implications from a false node can still be relied upon.
+	for n := range g.nodes {
+		if len(g.strong[n]) == 0 {
+			continue
+		}
+		for _, tn := range g.implications(n) {
+			changed, ok := a.eliminate(b, g.nodes[n], g.nodes[tn], n == tn)
+			if !ok {
+				return false
+			}
+			if changed {
+				a.AlgoStats.Hits++
+			}
+		}
+	}
+
+	return true
+}
+
+// eliminate makes the eliminations possible from knowing that at least one of
+// the 2 nodes is true. same indicates that n1 & n2 are the same node.
+func (a algoAIC) eliminate(b *Board, n1, n2 inferenceNode, same bool) (changed, ok bool) {
+	if same {
+		// the node must be true
+		if len(n1.tiles) == 1 {
+			ti := n1.tiles[0]
+			t0 := b.Tiles[ti]
+			return t0 != n1.v, b.set(ti, n1.v)
+		}
+		return eliminateFromCommonPeers(b, n1.v, n1.tiles...)
+	}
+
+	if n1.v == n2.v {
+		tis := make([]uint8, 0, len(n1.tiles)+len(n2.tiles))
+		tis = append(append(tis, n1.tiles...), n2.tiles...)
+		return eliminateFromCommonPeers(b, n1.v, tis...)
+	}
+
+	if len(n1.tiles) != 1 || len(n2.tiles) != 1 {
+		return false, true
+	}
+	ti1, ti2 := n1.tiles[0], n2.tiles[0]
+	if ti1 == ti2 {
+		return b.unset(ti1, ^(n1.v|n2.v)&tAny)
+	}
+	if !tilesSee(ti1, ti2) {
+		return false, true
+	}
+	changed1, ok := b.unset(ti1, n2.v)
+	if !ok {
+		return false, false
+	}
+	changed2, ok := b.unset(ti2, n1.v)
+	return changed1 || changed2, ok
+}
+
+// forcingChainsDefaultMaxDepth is the maximum number of rounds of propagation
+// used by algoForcingChains, when no maximum is given.
+const forcingChainsDefaultMaxDepth = 20
+
+// propagateSingles returns a copy of the board with the tile ti set to t, and
+// the consequences propagated using only the singles algorithms
+// (algoKnownValueElimination & algoOnePossibleTile). Propagation stops after
+// maxDepth rounds, even if there are more changes to make.
+// Returns false if the assumption leads to a contradiction.
+func propagateSingles(b *Board, ti uint8, t Tile, maxDepth int) (Board, bool) {
+	b0 := Board{Tiles: b.Tiles}
+	if !b0.set(ti, t) {
+		return b0, false
+	}
+	for depth := 0; depth < maxDepth && b0.hasChanges(); depth++ {
+		changes := b0.changes()
+		b0.clearChanges()
+		if !(algoKnownValueElimination{}).EvaluateChanges(&b0, changes) {
+			return b0, false
+		}
+		if !(algoOnePossibleTile{}).EvaluateChanges(&b0, changes) {
+			return b0, false
+		}
+	}
+	return b0, true
+}
+
+// forcingBranch is an assumption that the tile ti holds the value v.
+type forcingBranch struct {
+	ti uint8
+	v  Tile
+}
+
+// algoForcingChains finds forcing chains, by assuming a value, and propagating
+// the consequences with only the singles algorithms. It is a bounded, logical
+// alternative to guessing.
+//   - Cell forcing - Each possible value of a tile is assumed in turn. Any
+//     possibility eliminated by every assumption is eliminated.
+//   - Region forcing - Each tile in a neighbor set which can hold a value is
+//     assumed to hold it in turn. Any possibility eliminated by every assumption
+//     is eliminated.
+//
+// In both cases, an assumption which leads to a contradiction is eliminated.
+//
+// In Nishio mode, only the contradictions are used. Each possible value of each
+// tile is assumed, and eliminated if it leads to a contradiction.
+//
+// It is not part of the default algorithms added by NewBoard. See
+// ExpensiveAlgorithms.
+//
+// https://www.sudokuwiki.org/Cell_Forcing_Chains
+// https://www.sudokuwiki.org/Unit_Forcing_Chains
+// https://www.sudopedia.org/wiki/Nishio
+type algoForcingChains struct {
+	AlgoStats AlgorithmStats
+	// Nishio enables Nishio mode.
+	Nishio bool
+	// MaxDepth is the maximum number of rounds of propagation after each
+	// assumption. If 0, forcingChainsDefaultMaxDepth is used.
+	MaxDepth int
+}
+
+// NewForcingChains returns a new instance of the forcing chains algorithm, in
+// Nishio mode if nishio is true. It is also part of ExpensiveAlgorithms, but can
+// be appended to Board.Algorithms on its own with this.
+func NewForcingChains(nishio bool) Algorithm {
+	return &algoForcingChains{Nishio: nishio}
+}
+
+func (a algoForcingChains) Name() string {
+	if a.Nishio {
+		return "algoNishio"
+	}
+	return "algoForcingChains"
+}
+
+func (a *algoForcingChains) Stats() *AlgorithmStats { return &a.AlgoStats }
+
+func (a *algoForcingChains) EvaluateChanges(b *Board, changes []uint8) bool {
+	var branches []forcingBranch
+
+	// cell forcing
+	for ti := uint8(0); ti < 9*9; ti++ {
+		t := b.Tiles[ti]
+		if t.isKnown() {
+			continue
+		}
+		branches = branches[:0]
+		for _, v := range MaskBits[t] {
+			branches = append(branches, forcingBranch{ti, Tile(1 << v)})
+		}
+		if !a.force(b, branches) {
+			return false
+		}
+	}
+
+	if a.Nishio {
+		return true
+	}
+
+	// region forcing
+	for _, idcs := range NeighborSetIndices {
+	ValueLoop:
+		for v := Tile(1); v < tAny; v = v << 1 {
+			branches = branches[:0]
+			for _, ti := range idcs {
+				t := b.Tiles[ti]
+				if t == v {
+					// this value has already been found
+					continue ValueLoop
+				}
+				if t&v != 0 {
+					branches = append(branches, forcingBranch{ti, v})
+				}
+			}
+			if len(branches) < 2 {
+				// either a hidden single, or an invalid board. Either way, for the
+				// singles algorithms to handle.
+				continue
+			}
+			if !a.force(b, branches) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// force propagates each of the branches, of which at least one must be true,
+// and makes the resulting eliminations.
+// Returns false if every branch leads to a contradiction.
+func (a *algoForcingChains) force(b *Board, branches []forcingBranch) bool {
+	maxDepth := a.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = forcingChainsDefaultMaxDepth
+	}
+
+	changed := false
+	valid := 0
+	// union holds every possibility which remains after at least one of the
+	// branches.
+	var union [9 * 9]Tile
+	for _, br := range branches {
+		b0, ok := propagateSingles(b, br.ti, br.v, maxDepth)
+		if !ok {
+			// contradiction
+			c, ok := b.unset(br.ti, br.v)
+			if !ok {
+				return false
+			}
+			changed = changed || c
+			continue
+		}
+		valid++
+		for ti := range union {
+			union[ti] |= b0.Tiles[ti]
+		}
+	}
+	if valid == 0 {
+		return false
+	}
+
+	if !a.Nishio {
+		for ti, t := range union {
+			if b.Tiles[ti]&^t == 0 {
+				continue
+			}
+			if !b.set(uint8(ti), t) {
+				return false
+			}
+			changed = true
+		}
+	}
+
+	if changed {
+		a.AlgoStats.Hits++
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBoxLineSegments(t *testing.T) {
+	expected := [3]uint8{30, 31, 32}
+	if BoxLineSegments[4*3] != expected {
+		t.Errorf("BoxLineSegments[%d] is %v, expected %v", 4*3, BoxLineSegments[4*3], expected)
+	}
+	expected = [3]uint8{31, 40, 49}
+	if BoxLineSegments[27+4*3+1] != expected {
+		t.Errorf("BoxLineSegments[%d] is %v, expected %v", 27+4*3+1, BoxLineSegments[27+4*3+1], expected)
+	}
+}
+
+func TestInferenceGraph(t *testing.T) {
+	b := NewBoard()
+	b.set(0, numsTile(1, 2))
+
+	g := newInferenceGraph(&b, false)
+	n1 := g.tileNodes[0][0]
+	n2 := g.tileNodes[0][1]
+	if n1 < 0 || n2 < 0 {
+		t.Fatalf("missing nodes for tile 0")
+	}
+	if len(g.strong[n1]) != 1 || g.strong[n1][0] != n2 {
+		t.Errorf("g.strong[%d] is %v, expected [%d]", n1, g.strong[n1], n2)
+	}
+
+	// the 1 in tile 0 can't be true if the 1 in tile 1 is
+	trues := g.implications(g.tileNodes[1][0])
+	if len(trues) != 0 {
+		t.Errorf("implications are %v, expected none", trues)
+	}
+	trues = g.implications(n2)
+	if len(trues) != 1 || trues[0] != n1 {
+		t.Errorf("implications are %v, expected [%d]", trues, n1)
+	}
+}
+
+func TestAlgoAIC(t *testing.T) {
+	b := NewBoard()
+	// Build a grouped chain on the value 1:
+	// [0,0 1,0] =(row 0)= 6,0 -(column 6)- 6,4 =(row 4)= 2,4
+	for x := uint8(0); x < 9; x++ {
+		if x != 0 && x != 1 && x != 6 {
+			b.set(xyToIndex(x, 0), ^numsTile(1))
+		}
+		if x != 2 && x != 6 {
+			b.set(xyToIndex(x, 4), ^numsTile(1))
+		}
+	}
+
+	a := algoAIC{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// tiles which see both the group and 2,4
+	for _, ti := range []uint8{xyToIndex(2, 1), xyToIndex(2, 2)} {
+		if b.Tiles[ti]&numsTile(1) != 0 {
+			t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", ti, b.Tiles[ti], numsTile(1))
+		}
+	}
+	if b.Tiles[xyToIndex(2, 3)]&numsTile(1) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(2, 3), b.Tiles[xyToIndex(2, 3)], numsTile(1))
+	}
+}
+
+func TestAlgoForcingChains(t *testing.T) {
+	b := NewBoard()
+	// 0,0 [1,2]: 1 forces 4,0 [1,3] to 3, and 2 forces 0,4 [2,3] to 3
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(4, 0), numsTile(1, 3))
+	b.set(xyToIndex(0, 4), numsTile(2, 3))
+
+	a := algoForcingChains{}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	// sees 4,0 & 0,4, so can't be 3 either way
+	if b.Tiles[xyToIndex(4, 4)]&numsTile(3) != 0 {
+		t.Errorf("b.Tiles[%d] is %09b which includes %09b, expected it not to", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], numsTile(3))
+	}
+	if b.Tiles[xyToIndex(5, 5)]&numsTile(3) == 0 {
+		t.Errorf("b.Tiles[%d] is %09b, expected it to include %09b", xyToIndex(5, 5), b.Tiles[xyToIndex(5, 5)], numsTile(3))
+	}
+	if a.AlgoStats.Hits == 0 {
+		t.Errorf("a.AlgoStats.Hits is 0, expected >0")
+	}
+}
+
+func TestAlgoForcingChains_nishio(t *testing.T) {
+	b := NewBoard()
+	// 0,0 [1,2]: 1 forces both 1,0 [1,3] & 2,0 [1,3] to 3
+	b.set(xyToIndex(0, 0), numsTile(1, 2))
+	b.set(xyToIndex(1, 0), numsTile(1, 3))
+	b.set(xyToIndex(2, 0), numsTile(1, 3))
+
+	a := algoForcingChains{Nishio: true}
+	if a.Name() != "algoNishio" {
+		t.Errorf("a.Name() is %q, expected %q", a.Name(), "algoNishio")
+	}
+	if a.EvaluateChanges(&b, b.changes()) != true {
+		t.Errorf("EvaluateChanges is false, expected true")
+	}
+
+	if b.Tiles[xyToIndex(0, 0)] != numsTile(2) {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(0, 0), b.Tiles[xyToIndex(0, 0)], numsTile(2))
+	}
+	// nishio doesn't eliminate anything which isn't a contradiction
+	if b.Tiles[xyToIndex(4, 4)] != tAny {
+		t.Errorf("b.Tiles[%d] is %09b, expected %09b", xyToIndex(4, 4), b.Tiles[xyToIndex(4, 4)], tAny)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// dimacsVariable returns the DIMACS variable number (1-729) for the tile at the
+// given index holding the value v (0-8).
+// The variables are ordered by row, then column, then value. So the variable
+// for x,y holding the digit d is 81*y + 9*x + d.
+func dimacsVariable(ti uint8, v uint8) int {
+	return int(ti)*9 + int(v) + 1
+}
+
+// DIMACS encodes the board as a SAT problem in DIMACS CNF format. Any solution
+// of the problem is a solution of the board.
+// The current possible values of each tile are included, so tiles which are
+// known, or which have had possibilities eliminated, are constrained to match.
+// See dimacsVariable for the numbering of the variables.
+func (b Board) DIMACS() []byte {
+	var clauses [][]int
+
+	for ti, t := range b.Tiles {
+		ti := uint8(ti)
+		// the tile holds one of its possible values
+		var clause []int
+		for v := uint8(0); v < 9; v++ {
+			if t&(1<<v) != 0 {
+				clause = append(clause, dimacsVariable(ti, v))
+			} else {
+				clauses = append(clauses, []int{-dimacsVariable(ti, v)})
+			}
+		}
+		clauses = append(clauses, clause)
+
+		// the tile holds at most one value
+		for v1 := uint8(0); v1 < 9; v1++ {
+			for v2 := v1 + 1; v2 < 9; v2++ {
+				clauses = append(clauses, []int{-dimacsVariable(ti, v1), -dimacsVariable(ti, v2)})
+			}
+		}
+	}
+
+	for _, idcs := range NeighborSetIndices {
+		for v := uint8(0); v < 9; v++ {
+			// the value is held by one of the tiles in the neighbor set
+			var clause []int
+			for _, ti := range idcs {
+				clause = append(clause, dimacsVariable(ti, v))
+			}
+			clauses = append(clauses, clause)
+
+			// the value is held by at most one of them
+			for i, ti1 := range idcs {
+				for _, ti2 := range idcs[i+1:] {
+					clauses = append(clauses, []int{-dimacsVariable(ti1, v), -dimacsVariable(ti2, v)})
+				}
+			}
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "c soodohkoo sudoku board. Variable 81*y + 9*x + d means tile x,y (0-8) holds digit d (1-9).\n")
+	fmt.Fprintf(buf, "p cnf %d %d\n", 9*9*9, len(clauses))
+	for _, clause := range clauses {
+		for _, lit := range clause {
+			buf.WriteString(strconv.Itoa(lit))
+			buf.WriteByte(' ')
+		}
+		buf.WriteString("0\n")
+	}
+	return buf.Bytes()
+}
+
+// ReadDIMACSModel reads a SAT solver's model of the problem produced by DIMACS,
+// and sets the tiles of the board to match it.
+// Both the SAT competition output format ("s SATISFIABLE" followed by "v" lines)
+// and the MiniSat format ("SAT" followed by the literals) are accepted.
+// An error is returned if the model is unsatisfiable, incomplete, conflicts with
+// the board, or has a value more than once in a row, column, or region.
+func (b *Board) ReadDIMACSModel(r io.Reader) error {
+	var values [9 * 9]Tile
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "", strings.HasPrefix(line, "c"):
+			continue
+		case line == "UNSAT", line == "s UNSATISFIABLE":
+			return errors.New("invalid board: no solution")
+		case line == "SAT", strings.HasPrefix(line, "s "):
+			continue
+		case strings.HasPrefix(line, "v "):
+			line = line[2:]
+		}
+
+		for _, field := range strings.Fields(line) {
+			lit, err := strconv.Atoi(field)
+			if err != nil {
+				return fmt.Errorf("invalid literal %q", field)
+			}
+			if lit <= 0 {
+				continue
+			}
+			if lit > 9*9*9 {
+				return fmt.Errorf("invalid variable %d", lit)
+			}
+			ti, v := (lit-1)/9, (lit-1)%9
+			values[ti] |= 1 << uint(v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	b0 := *b
+	for ti, t := range values {
+		if t == 0 || !t.isKnown() {
+			return errors.New("invalid model: tile does not have a single value")
+		}
+		if !b0.set(uint8(ti), t) {
+			return errors.New("invalid model: conflicts with board")
+		}
+	}
+	for _, idcs := range NeighborSetIndices {
+		var seen Tile
+		for _, ti := range idcs {
+			if seen&values[ti] != 0 {
+				return errors.New("invalid model: violates sudoku constraints")
+			}
+			seen |= values[ti]
+		}
+	}
+	*b = b0
+	return nil
+}
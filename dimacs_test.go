@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const dimacsTestBoard = `_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`
+
+const dimacsTestSolution = `1 8 7 3 6 9 4 5 2
+5 4 6 2 8 7 9 3 1
+9 3 2 1 5 4 8 6 7
+2 1 9 5 3 8 7 4 6
+4 6 5 9 7 2 3 1 8
+3 7 8 6 4 1 2 9 5
+7 5 4 8 9 6 1 2 3
+8 2 3 4 1 5 6 7 9
+6 9 1 7 2 3 5 8 4
+`
+
+// dimacsTestModel builds a model, in the SAT competition format, from a solved
+// board.
+func dimacsTestModel(b Board) string {
+	buf := bytes.NewBufferString("s SATISFIABLE\nv")
+	for ti, t := range b.Tiles {
+		for v := uint8(0); v < 9; v++ {
+			lit := dimacsVariable(uint8(ti), v)
+			if t != 1<<v {
+				lit = -lit
+			}
+			fmt.Fprintf(buf, " %d", lit)
+		}
+	}
+	buf.WriteString(" 0\n")
+	return buf.String()
+}
+
+// dimacsSatisfied indicates whether every clause of the CNF is satisfied by the
+// values of the solved board.
+func dimacsSatisfied(t *testing.T, cnf []byte, solution Board) bool {
+	lines := strings.Split(strings.TrimSpace(string(cnf)), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "c") || strings.HasPrefix(line, "p") {
+			continue
+		}
+		satisfied := false
+		for _, field := range strings.Fields(line) {
+			lit, err := strconv.Atoi(field)
+			if err != nil {
+				t.Fatalf("invalid literal %q", field)
+			}
+			if lit == 0 {
+				break
+			}
+			variable := lit
+			if lit < 0 {
+				variable = -lit
+			}
+			ti, v := (variable-1)/9, (variable-1)%9
+			if (solution.Tiles[ti] == 1<<uint(v)) == (lit > 0) {
+				satisfied = true
+			}
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDIMACS(t *testing.T) {
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(dimacsTestBoard))
+	cnf := b.DIMACS()
+
+	if !bytes.Contains(cnf, []byte("\np cnf 729 ")) {
+		t.Errorf("DIMACS() is missing the problem line")
+	}
+	// tile 1 is known to be 8
+	if !bytes.Contains(cnf, []byte(fmt.Sprintf("\n%d 0\n", dimacsVariable(1, 7)))) {
+		t.Errorf("DIMACS() is missing the unit clause for tile 1")
+	}
+
+	solution := NewBoard()
+	solution.ReadFrom(strings.NewReader(dimacsTestSolution))
+	if !dimacsSatisfied(t, cnf, solution) {
+		t.Errorf("DIMACS() is not satisfied by the solution")
+	}
+
+	// swap 2 values of the solution, which keeps the tiles valid, but breaks the
+	// rows & the given at tile 1
+	solution.Tiles[0], solution.Tiles[1] = solution.Tiles[1], solution.Tiles[0]
+	if dimacsSatisfied(t, cnf, solution) {
+		t.Errorf("DIMACS() is satisfied by an invalid solution")
+	}
+}
+
+func TestReadDIMACSModel(t *testing.T) {
+	solution := NewBoard()
+	solution.ReadFrom(strings.NewReader(dimacsTestSolution))
+	model := dimacsTestModel(solution)
+
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(dimacsTestBoard))
+	if err := b.ReadDIMACSModel(strings.NewReader(model)); err != nil {
+		t.Fatalf("b.ReadDIMACSModel() returned error when none expected: %s", err)
+	}
+	if b.Tiles != solution.Tiles {
+		t.Errorf("b.Tiles is\n%s\nexpected\n%s", b.Art(), solution.Art())
+	}
+
+	// MiniSat format
+	b = NewBoard()
+	b.ReadFrom(strings.NewReader(dimacsTestBoard))
+	model = "SAT\n" + strings.TrimPrefix(model, "s SATISFIABLE\nv ")
+	if err := b.ReadDIMACSModel(strings.NewReader(model)); err != nil {
+		t.Fatalf("b.ReadDIMACSModel() returned error when none expected: %s", err)
+	}
+	if b.Tiles != solution.Tiles {
+		t.Errorf("b.Tiles is\n%s\nexpected\n%s", b.Art(), solution.Art())
+	}
+}
+
+func TestReadDIMACSModel_invalid(t *testing.T) {
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(dimacsTestBoard))
+	b0 := b
+
+	if err := b.ReadDIMACSModel(strings.NewReader("s UNSATISFIABLE\n")); err == nil {
+		t.Errorf("b.ReadDIMACSModel() returned no error for an unsatisfiable model")
+	}
+
+	// a model which conflicts with the given at tile 1
+	solution := NewBoard()
+	solution.ReadFrom(strings.NewReader(dimacsTestSolution))
+	solution.Tiles[0], solution.Tiles[1] = solution.Tiles[1], solution.Tiles[0]
+	if err := b.ReadDIMACSModel(strings.NewReader(dimacsTestModel(solution))); err == nil {
+		t.Errorf("b.ReadDIMACSModel() returned no error for a conflicting model")
+	}
+	if b.Tiles != b0.Tiles {
+		t.Errorf("b.ReadDIMACSModel() modified the board on error")
+	}
+
+	// a model which is complete, but has 1 in every tile
+	b = NewBoard()
+	ones := NewBoard()
+	for ti := range ones.Tiles {
+		ones.Tiles[ti] = 1
+	}
+	err := b.ReadDIMACSModel(strings.NewReader(dimacsTestModel(ones)))
+	if err == nil || err.Error() != "invalid model: violates sudoku constraints" {
+		t.Errorf("b.ReadDIMACSModel() returned %v for a model which violates the sudoku constraints", err)
+	}
+	if b.Tiles != NewBoard().Tiles {
+		t.Errorf("b.ReadDIMACSModel() modified the board on error")
+	}
+}
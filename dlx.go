@@ -0,0 +1,181 @@
+package main
+
+import "time"
+
+// dlxColumns is the number of constraint columns in the exact cover matrix of a
+// board. Each of 81 tiles must hold a value, and each of the 9 values must be
+// held once within each of the 9 rows, 9 columns, & 9 regions.
+const dlxColumns = 9 * 9 * 4
+
+// dlxMatrix is an exact cover matrix of a board, for solving with Knuth's
+// Algorithm X using dancing links (DLX).
+// Each row of the matrix is a possible value of a tile, and each column a
+// constraint which must be satisfied by exactly one row.
+// The nodes are stored in parallel slices. Node 0 is the root, nodes 1 through
+// dlxColumns are the column headers, and the rest are the nodes of each row.
+type dlxMatrix struct {
+	left, right, up, down []int
+	// column is the column header of each node.
+	column []int
+	// candidate is the tile index & value (0-8) of each node, as ti*9+v.
+	candidate []int
+	// size is the number of nodes within each column, indexed by column header.
+	size []int
+
+	// solution holds the row nodes of the solution so far.
+	solution []int
+}
+
+// newDLXMatrix builds the exact cover matrix for the possible values of the
+// tiles on the board.
+func newDLXMatrix(b *Board) *dlxMatrix {
+	m := &dlxMatrix{}
+	for i := 0; i <= dlxColumns; i++ {
+		m.left = append(m.left, i-1)
+		m.right = append(m.right, i+1)
+		m.up = append(m.up, i)
+		m.down = append(m.down, i)
+		m.column = append(m.column, i)
+		m.candidate = append(m.candidate, -1)
+		m.size = append(m.size, 0)
+	}
+	m.left[0] = dlxColumns
+	m.right[dlxColumns] = 0
+
+	for ti, t := range b.Tiles {
+		x, y := indexToXY(uint8(ti))
+		rgnIdx := int(tileIndexToRegionIndex(uint8(ti)))
+		for _, v := range MaskBits[t] {
+			v := int(v)
+			m.addRow(ti*9+v, [4]int{
+				1 + ti,
+				1 + 81 + int(y)*9 + v,
+				1 + 81*2 + int(x)*9 + v,
+				1 + 81*3 + rgnIdx*9 + v,
+			})
+		}
+	}
+	return m
+}
+
+// addRow adds a row to the matrix for the given candidate, with a node in each
+// of the given columns.
+func (m *dlxMatrix) addRow(candidate int, columns [4]int) {
+	first := len(m.left)
+	for i, c := range columns {
+		n := len(m.left)
+		m.left = append(m.left, n-1)
+		m.right = append(m.right, n+1)
+		if i == 0 {
+			m.left[n] = first + len(columns) - 1
+		}
+		if i == len(columns)-1 {
+			m.right[n] = first
+		}
+		m.up = append(m.up, m.up[c])
+		m.down = append(m.down, c)
+		m.down[m.up[c]] = n
+		m.up[c] = n
+		m.column = append(m.column, c)
+		m.candidate = append(m.candidate, candidate)
+		m.size[c]++
+	}
+}
+
+// cover removes the column c from the header list, and all rows which have a
+// node in the column from the other columns.
+func (m *dlxMatrix) cover(c int) {
+	m.right[m.left[c]] = m.right[c]
+	m.left[m.right[c]] = m.left[c]
+	for i := m.down[c]; i != c; i = m.down[i] {
+		for j := m.right[i]; j != i; j = m.right[j] {
+			m.down[m.up[j]] = m.down[j]
+			m.up[m.down[j]] = m.up[j]
+			m.size[m.column[j]]--
+		}
+	}
+}
+
+// uncover reverses cover.
+func (m *dlxMatrix) uncover(c int) {
+	for i := m.up[c]; i != c; i = m.up[i] {
+		for j := m.left[i]; j != i; j = m.left[j] {
+			m.size[m.column[j]]++
+			m.down[m.up[j]] = j
+			m.up[m.down[j]] = j
+		}
+	}
+	m.right[m.left[c]] = c
+	m.left[m.right[c]] = c
+}
+
+// search finds each exact cover, calling fn with the row nodes of each one.
+// Returns false once fn returns false, to stop the search.
+func (m *dlxMatrix) search(fn func(solution []int) bool) bool {
+	if m.right[0] == 0 {
+		return fn(m.solution)
+	}
+
+	// choose the column with the fewest rows
+	c := m.right[0]
+	for j := m.right[c]; j != 0; j = m.right[j] {
+		if m.size[j] < m.size[c] {
+			c = j
+		}
+	}
+	if m.size[c] == 0 {
+		return true
+	}
+
+	m.cover(c)
+	defer m.uncover(c)
+	for r := m.down[c]; r != c; r = m.down[r] {
+		m.solution = append(m.solution, r)
+		for j := m.right[r]; j != r; j = m.right[j] {
+			m.cover(m.column[j])
+		}
+		ok := m.search(fn)
+		for j := m.left[r]; j != r; j = m.left[j] {
+			m.uncover(m.column[j])
+		}
+		m.solution = m.solution[:len(m.solution)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// eachSolutionDLX is the DLX engine's implementation of EachSolution.
+func (b *Board) eachSolutionDLX(limit int, fn func(Board) bool) int {
+	m := newDLXMatrix(b)
+	count := 0
+	m.search(func(solution []int) bool {
+		count++
+		b0 := *b
+		for _, n := range solution {
+			ti, v := m.candidate[n]/9, m.candidate[n]%9
+			b0.Tiles[ti] = 1 << uint(v)
+		}
+		b0.clearChanges()
+		return fn(b0) && (limit == 0 || count < limit)
+	})
+	return count
+}
+
+// solveDLX is the DLX engine's implementation of Solve.
+func (b *Board) solveDLX() bool {
+	b.guessStats.Calls++
+	tStart := time.Now()
+	defer func() {
+		b.guessStats.Duration += time.Now().Sub(tStart)
+	}()
+
+	solved := false
+	b.eachSolutionDLX(1, func(solution Board) bool {
+		*b = solution
+		solved = true
+		return false
+	})
+	return solved
+}
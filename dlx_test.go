@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSolveDLX(t *testing.T) {
+	b := NewBoard()
+	b.Engine = EngineDLX
+	b.ReadFrom(strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`))
+	if !b.Solve() {
+		t.Fatalf("b.Solve() is false, expected true")
+	}
+
+	expected := NewBoard()
+	expected.ReadFrom(strings.NewReader(`1 8 7 3 6 9 4 5 2
+5 4 6 2 8 7 9 3 1
+9 3 2 1 5 4 8 6 7
+2 1 9 5 3 8 7 4 6
+4 6 5 9 7 2 3 1 8
+3 7 8 6 4 1 2 9 5
+7 5 4 8 9 6 1 2 3
+8 2 3 4 1 5 6 7 9
+6 9 1 7 2 3 5 8 4
+`))
+	if b.Tiles != expected.Tiles {
+		t.Errorf("b.Tiles is\n%s\nexpected\n%s", b.Art(), expected.Art())
+	}
+	if b.guessStats.Calls != 1 {
+		t.Errorf("b.guessStats.Calls is %d, expected %d", b.guessStats.Calls, 1)
+	}
+}
+
+func TestSolveDLX_noSolution(t *testing.T) {
+	b := NewBoard()
+	b.Engine = EngineDLX
+	b.set(0, numsTile(1))
+	b.set(1, numsTile(1))
+	b0 := b
+	if b.Solve() {
+		t.Errorf("b.Solve() is true, expected false")
+	}
+	if b.Tiles != b0.Tiles {
+		t.Errorf("b.Solve() modified the board")
+	}
+}
+
+func TestEachSolutionDLX(t *testing.T) {
+	// an empty board except for a single row, restricted to the candidates of 2
+	// tiles
+	b := NewBoard()
+	b.ReadFrom(strings.NewReader(`1 2 3 4 5 6 7 8 9
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+`))
+	b.Engine = EngineDLX
+	n := b.EachSolution(10, func(solution Board) bool {
+		if !solution.Solved() {
+			t.Errorf("solution.Solved() is false, expected true")
+		}
+		if solution.Tiles[0] != numsTile(1) {
+			t.Errorf("solution.Tiles[0] is %09b, expected %09b", solution.Tiles[0], numsTile(1))
+		}
+		return true
+	})
+	if n != 10 {
+		t.Errorf("b.EachSolution(10) found %d, expected %d", n, 10)
+	}
+}
@@ -43,8 +43,8 @@ func (a algoGenerateShuffle) EvaluateChanges(b *Board, changes []uint8) bool {
 // the number of tiles to set as unknown.
 // Note that the actual number of unknown tiles may be less than the number
 // requested if the algorithm can remove no further tiles.
-func NewRandomBoard(difficulty int) Board {
-	b := NewBoard()
+func NewRandomBoard(difficulty int) (b Board) {
+	b = NewBoard()
 
 	// The board is deterministic by seed.
 	// Meaning the same seed always generates the same board.
@@ -54,6 +54,7 @@ func NewRandomBoard(difficulty int) Board {
 	// restore MaskBits to its original value after we're done.
 	defer func(mbs [512][]uint8) { MaskBits = mbs }(MaskBits)
 
+	// b is a named result, so that this also applies to the returned board.
 	defer func(algos []Algorithm) { b.Algorithms = algos }(b.Algorithms)
 	b.Algorithms = append(b.Algorithms, &algoGenerateShuffle{rng})
 
@@ -150,8 +151,7 @@ func (b *Board) dropRandomTile(rng *rand.Rand) bool {
 		// multiple solutions. So retry with a different tile.
 		bTest := *b
 		bTest.Tiles[ti] = (^bTest.Tiles[ti]) & tAny
-		bTest.changeSet[ti/27] |= 1 << (ti % 27)
-		if bTest.Solve() {
+		if bTest.CountSolutions(1) > 0 {
 			// Have multiple solutions. Try again
 			continue
 		}
@@ -8,6 +8,7 @@ import (
 )
 
 func TestAlgoGeneratorShuffle(t *testing.T) {
+	defer func(mbs [512][]uint8) { MaskBits = mbs }(MaskBits)
 	a := algoGenerateShuffle{rand.New(rand.NewSource(0))}
 	mbs := fmt.Sprintf("%v", MaskBits[numsTile(1, 3, 5, 7)])
 	a.EvaluateChanges(nil, nil)
@@ -29,6 +30,12 @@ func TestNewRandomBoard(t *testing.T) {
 		t.Errorf("unknownTiles is %d, expected %d", unknownTiles, 5)
 	}
 
+	for _, a := range b.Algorithms {
+		if _, ok := a.(*algoGenerateShuffle); ok {
+			t.Errorf("b.Algorithms contains algoGenerateShuffle, expected it to be removed")
+		}
+	}
+
 	if !b.Solve() {
 		t.Errorf("b.Solve() is false, expected true")
 	}
@@ -0,0 +1,90 @@
+package main
+
+import "sort"
+
+// TileSelector returns the index of the tile which should have its value
+// guessed, or 255 if all tiles have a known value.
+type TileSelector func(b *Board) uint8
+
+// ValueOrderer returns the possible values (0-8) of the tile at index ti, in the
+// order they should be guessed.
+type ValueOrderer func(b *Board, ti uint8) []uint8
+
+// SelectMRV selects the first tile with the least amount of possible values
+// (minimum remaining values).
+func SelectMRV(b *Board) uint8 {
+	uti := uint8(255)
+	utPossibilityCount := uint8(255)
+	for ti := range b.Tiles {
+		t := b.Tiles[ti]
+		if t.isKnown() {
+			continue
+		}
+		pc := uint8(len(MaskBits[t]))
+		if pc < utPossibilityCount {
+			uti = uint8(ti)
+			utPossibilityCount = pc
+			if pc == 2 {
+				// can't get less than 2 and still be unknown
+				break
+			}
+		}
+	}
+	return uti
+}
+
+// SelectMRVDegree selects the tile with the least amount of possible values
+// (minimum remaining values), and breaks ties by choosing the tile with the most
+// peers which don't have a known value (the most constrained peers).
+func SelectMRVDegree(b *Board) uint8 {
+	uti := uint8(255)
+	utPossibilityCount := 255
+	utDegree := -1
+	for ti := range b.Tiles {
+		t := b.Tiles[ti]
+		if t.isKnown() {
+			continue
+		}
+		pc := len(MaskBits[t])
+		if pc > utPossibilityCount {
+			continue
+		}
+		degree := 0
+		for _, nti := range PeerIndices[ti][:] {
+			if !b.Tiles[nti].isKnown() {
+				degree++
+			}
+		}
+		if pc < utPossibilityCount || degree > utDegree {
+			uti = uint8(ti)
+			utPossibilityCount = pc
+			utDegree = degree
+		}
+	}
+	return uti
+}
+
+// OrderNatural orders the values as they are in MaskBits. This is ascending
+// order, unless MaskBits has been shuffled by the generator.
+func OrderNatural(b *Board, ti uint8) []uint8 {
+	return MaskBits[b.Tiles[ti]]
+}
+
+// OrderLCV orders the values so that the value which eliminates the fewest
+// possibilities from the tile's peers (least constraining value) is first.
+// Ties are kept in the order of MaskBits.
+func OrderLCV(b *Board, ti uint8) []uint8 {
+	vs := append([]uint8(nil), MaskBits[b.Tiles[ti]]...)
+	var eliminations [9]int
+	for _, nti := range PeerIndices[ti][:] {
+		nt := b.Tiles[nti]
+		if nt.isKnown() {
+			continue
+		}
+		for _, v := range MaskBits[nt] {
+			eliminations[v]++
+		}
+	}
+	sort.SliceStable(vs, func(i, j int) bool { return eliminations[vs[i]] < eliminations[vs[j]] })
+	return vs
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectMRVDegree(t *testing.T) {
+	b := NewBoard()
+	b.Tiles[0] = 0b11
+	b.Tiles[80] = 0b11
+	// make a peer of tile 0 known, so tile 80 has more unknown peers
+	b.Tiles[1] = 1 << 5
+
+	if uti := SelectMRV(&b); uti != 0 {
+		t.Errorf("SelectMRV() is %d, expected %d", uti, 0)
+	}
+	if uti := SelectMRVDegree(&b); uti != 80 {
+		t.Errorf("SelectMRVDegree() is %d, expected %d", uti, 80)
+	}
+
+	for ti := range b.Tiles {
+		b.Tiles[ti] = 1 << 0
+	}
+	if uti := SelectMRVDegree(&b); uti != 255 {
+		t.Errorf("SelectMRVDegree() on a solved board is %d, expected %d", uti, 255)
+	}
+}
+
+func TestOrderLCV(t *testing.T) {
+	b := NewBoard()
+	b.Tiles[0] = 0b111
+	for _, nti := range PeerIndices[0] {
+		b.Tiles[nti] = 0b110000011
+	}
+	// value 1 is possible in one less peer than value 0, and value 2 in none
+	b.Tiles[PeerIndices[0][0]] = 0b110000001
+
+	vs := OrderLCV(&b, 0)
+	expected := []uint8{2, 1, 0}
+	if len(vs) != len(expected) {
+		t.Fatalf("OrderLCV() is %v, expected %v", vs, expected)
+	}
+	for i := range vs {
+		if vs[i] != expected[i] {
+			t.Fatalf("OrderLCV() is %v, expected %v", vs, expected)
+		}
+	}
+
+	expected = []uint8{0, 1, 2}
+	vs = OrderNatural(&b, 0)
+	if len(vs) != len(expected) {
+		t.Fatalf("OrderNatural() is %v, expected %v", vs, expected)
+	}
+	for i := range vs {
+		if vs[i] != expected[i] {
+			t.Fatalf("OrderNatural() is %v, expected %v", vs, expected)
+		}
+	}
+}
+
+func TestGuess_heuristics(t *testing.T) {
+	input := `_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`
+	var solution [81]Tile
+	for _, ts := range []TileSelector{nil, SelectMRV, SelectMRVDegree} {
+		for _, vo := range []ValueOrderer{nil, OrderNatural, OrderLCV} {
+			b := NewBoard()
+			// use only the basic algorithms, so that guessing is needed
+			b.Algorithms = []Algorithm{&algoKnownValueElimination{}, &algoOnePossibleTile{}}
+			b.TileSelector = ts
+			b.ValueOrderer = vo
+			if _, err := b.ReadFrom(strings.NewReader(input)); err != nil {
+				t.Fatalf("error reading board: %s", err)
+			}
+			if !b.Solve() {
+				t.Fatalf("b.Solve() is false, expected true")
+			}
+			if b.guessStats.Calls == 0 {
+				t.Errorf("guesser was not called")
+			}
+			if solution[0] == 0 {
+				solution = b.Tiles
+			} else if b.Tiles != solution {
+				t.Errorf("solution differs between heuristics")
+			}
+		}
+	}
+}
@@ -11,6 +11,21 @@ import (
 	"sync"
 )
 
+var engines = map[string]Engine{
+	"algorithms": EngineAlgorithms,
+	"dlx":        EngineDLX,
+}
+
+var tileSelectors = map[string]TileSelector{
+	"mrv":       SelectMRV,
+	"mrvDegree": SelectMRVDegree,
+}
+
+var valueOrderers = map[string]ValueOrderer{
+	"natural": OrderNatural,
+	"lcv":     OrderLCV,
+}
+
 var difficulties = map[string]int{
 	"easy":   45,
 	"medium": 50,
@@ -22,17 +37,60 @@ func main() {
 	os.Exit(mainMain())
 }
 func mainMain() int {
-	mode := flag.String("mode", "solve", "Operation mode {solve|solveStream|generate}")
+	mode := flag.String("mode", "solve", "Operation mode {solve|solveStream|classify|enumerate|dimacs|applyModel|generate}")
 	difficulty := flag.String("difficulty", "medium", "Difficulty of generated board {easy|medium|hard|insane|1-70}")
 	showStats := flag.Bool("stats", false, "show solver statistics")
+	assumeUnique := flag.Bool("unique", false, "assume the board has a single solution, enabling the uniqueness algorithms")
+	expensive := flag.Bool("expensive", false, "enable the expensive algorithms")
+	forcingChains := flag.Bool("forcingChains", false, "enable the forcing chains algorithm")
+	nishio := flag.Bool("nishio", false, "enable the forcing chains algorithm in Nishio mode")
+	engineName := flag.String("engine", "algorithms", "solving engine {algorithms|dlx}")
+	guessTileName := flag.String("guessTile", "mrv", "heuristic for choosing the tile to guess {mrv|mrvDegree}")
+	guessOrderName := flag.String("guessOrder", "natural", "heuristic for ordering the values to guess {natural|lcv}")
+	modelPath := flag.String("model", "", "path of the SAT model file for applyModel mode")
+	limit := flag.Int("limit", 0, "maximum number of solutions to write in enumerate mode (0 for no limit)")
 	flag.Parse()
 
+	engine, ok := engines[*engineName]
+	if !ok {
+		flag.Usage()
+		return 1
+	}
+	tileSelector, ok := tileSelectors[*guessTileName]
+	if !ok {
+		flag.Usage()
+		return 1
+	}
+	valueOrderer, ok := valueOrderers[*guessOrderName]
+	if !ok {
+		flag.Usage()
+		return 1
+	}
+	opts := solveOptions{
+		engine:        engine,
+		tileSelector:  tileSelector,
+		valueOrderer:  valueOrderer,
+		showStats:     *showStats,
+		assumeUnique:  *assumeUnique,
+		expensive:     *expensive,
+		forcingChains: *forcingChains,
+		nishio:        *nishio,
+	}
+
 	var err error
 	switch *mode {
 	case "solve":
-		err = mainSolveOne(*showStats)
+		err = mainSolveOne(opts)
 	case "solveStream":
-		err = mainSolveStream(*showStats)
+		err = mainSolveStream(opts)
+	case "classify":
+		err = mainClassifyStream()
+	case "enumerate":
+		err = mainEnumerate(*limit, opts)
+	case "dimacs":
+		err = mainDIMACS()
+	case "applyModel":
+		err = mainApplyModel(*modelPath)
 	case "generate":
 		err = mainGenerate(*difficulty)
 	default:
@@ -46,8 +104,50 @@ func mainMain() int {
 	return 0
 }
 
-func mainSolveReader(input io.Reader, showStats bool) ([]byte, error) {
+// solveOptions are the options for the solve modes.
+type solveOptions struct {
+	// engine is the solving engine to use.
+	engine Engine
+	// tileSelector chooses the tile to guess.
+	tileSelector TileSelector
+	// valueOrderer orders the values to guess.
+	valueOrderer ValueOrderer
+	// showStats shows the algorithm statistics after the solution.
+	showStats bool
+	// assumeUnique enables the uniqueness algorithms.
+	assumeUnique bool
+	// expensive enables the expensive algorithms.
+	expensive bool
+	// forcingChains enables the forcing chains algorithm, which is also one of
+	// the expensive algorithms.
+	forcingChains bool
+	// nishio enables the forcing chains algorithm in Nishio mode.
+	nishio bool
+}
+
+// newSolveBoard creates a new board with the engine, guess heuristics, and
+// algorithms selected by opts.
+func newSolveBoard(opts solveOptions) Board {
 	b := NewBoard()
+	b.Engine = opts.engine
+	b.TileSelector = opts.tileSelector
+	b.ValueOrderer = opts.valueOrderer
+	if opts.assumeUnique {
+		b.Algorithms = append(b.Algorithms, UniquenessAlgorithms()...)
+	}
+	if opts.expensive {
+		b.Algorithms = append(b.Algorithms, ExpensiveAlgorithms()...)
+	} else if opts.forcingChains {
+		b.Algorithms = append(b.Algorithms, NewForcingChains(false))
+	}
+	if opts.nishio {
+		b.Algorithms = append(b.Algorithms, NewForcingChains(true))
+	}
+	return b
+}
+
+func mainSolveReader(input io.Reader, opts solveOptions) ([]byte, error) {
+	b := newSolveBoard(opts)
 	_, err := b.ReadFrom(input)
 	if err != nil {
 		return nil, err
@@ -60,7 +160,7 @@ func mainSolveReader(input io.Reader, showStats bool) ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
 	buf.Write(b.Art())
 
-	if showStats {
+	if opts.showStats {
 		fmt.Fprintf(buf, "Stats:\n")
 		fmt.Fprintf(buf, "  %-30s %8s %8s %14s\n", "Algorithm", "Calls", "Changes", "Duration (ns)")
 		for _, a := range b.Algorithms {
@@ -68,14 +168,18 @@ func mainSolveReader(input io.Reader, showStats bool) ([]byte, error) {
 			fmt.Fprintf(buf, "  %-30s %8d %8d %14d\n", a.Name(), stats.Calls, stats.Changes, stats.Duration)
 		}
 		stats := b.guessStats
-		fmt.Fprintf(buf, "  %-30s %8d %8d %14d\n", "guesser", stats.Calls, stats.Changes, stats.Duration)
+		name := "guesser"
+		if b.Engine == EngineDLX {
+			name = "dlx"
+		}
+		fmt.Fprintf(buf, "  %-30s %8d %8d %14d\n", name, stats.Calls, stats.Changes, stats.Duration)
 	}
 
 	return buf.Bytes(), nil
 }
 
-func mainSolveOne(showStats bool) error {
-	out, err := mainSolveReader(os.Stdin, showStats)
+func mainSolveOne(opts solveOptions) error {
+	out, err := mainSolveReader(os.Stdin, opts)
 	if err != nil {
 		return err
 	}
@@ -89,7 +193,16 @@ type streamJob struct {
 	wg  sync.WaitGroup
 }
 
-func mainSolveStream(showStats bool) error {
+func mainSolveStream(opts solveOptions) error {
+	return mainStream(func(input io.Reader) ([]byte, error) {
+		return mainSolveReader(input, opts)
+	})
+}
+
+// mainStream reads boards from STDIN, processes them in parallel, and writes the
+// output of each to STDOUT, in the same order as the input. It stops at the first
+// error.
+func mainStream(process func(io.Reader) ([]byte, error)) error {
 	wg := sync.WaitGroup{}
 	defer wg.Wait()
 
@@ -103,7 +216,7 @@ func mainSolveStream(showStats bool) error {
 		go func() {
 			for job := range workerJobs {
 				buf := bytes.NewBuffer(job.bs)
-				job.bs, job.err = mainSolveReader(buf, showStats)
+				job.bs, job.err = process(buf)
 				job.wg.Done()
 			}
 			wg.Done()
@@ -155,6 +268,80 @@ func mainSolveStream(showStats bool) error {
 	return <-errChan
 }
 
+// mainClassifyStream reads multiple boards from STDIN, and prints whether each
+// one has no solution, a unique solution, or multiple solutions.
+func mainClassifyStream() error {
+	return mainStream(mainClassifyReader)
+}
+
+func mainClassifyReader(input io.Reader) ([]byte, error) {
+	b := NewBoard()
+	if _, err := b.ReadFrom(input); err != nil {
+		return nil, err
+	}
+	return []byte(b.ClassifySolutions().String() + "\n"), nil
+}
+
+// mainEnumerate reads a board from STDIN, and writes each of its solutions, up
+// to limit solutions (0 for no limit). opts.showStats is ignored, and the
+// uniqueness algorithms are never used by EachSolution.
+func mainEnumerate(limit int, opts solveOptions) error {
+	b := newSolveBoard(opts)
+	_, err := b.ReadFrom(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	n := b.EachSolution(limit, func(solution Board) bool {
+		_, err = os.Stdout.Write(solution.Art())
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("invalid board: no solution")
+	}
+	return nil
+}
+
+// mainDIMACS reads a board from STDIN, and writes it as a SAT problem in DIMACS
+// CNF format.
+func mainDIMACS() error {
+	b := NewBoard()
+	_, err := b.ReadFrom(os.Stdin)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b.DIMACS())
+	return err
+}
+
+// mainApplyModel reads a board from STDIN, and a SAT solver's model of the
+// board's DIMACS problem from the file at modelPath, and writes the board with
+// the model applied.
+func mainApplyModel(modelPath string) error {
+	if modelPath == "" {
+		return fmt.Errorf("-model is required")
+	}
+	b := NewBoard()
+	_, err := b.ReadFrom(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(modelPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := b.ReadDIMACSModel(f); err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b.Art())
+	return err
+}
+
 func mainGenerate(difficulty string) error {
 	lvl := difficulties[difficulty]
 	if lvl == 0 {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
 	"sync"
@@ -227,3 +228,304 @@ func TestMainGenerate_difficultyInt(t *testing.T) {
 		t.Errorf("have %d unknown tiles, expected %d", unknownCount, 3)
 	}
 }
+
+func TestMainSolve_unique(t *testing.T) {
+	input := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`)
+	status, output := runMain(t, input, "-mode=solve", "-unique", "-stats")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	if !strings.Contains(output.String(), "algoUniqueRectangle") {
+		t.Errorf("output does not contain stats for algoUniqueRectangle")
+	}
+
+	b := NewBoard()
+	_, err := b.ReadFrom(output)
+	if err != nil {
+		t.Errorf("error reading output board: %s", err)
+	}
+	if !b.Solved() {
+		t.Errorf("output board is not solved")
+	}
+}
+
+func TestMainSolve_expensive(t *testing.T) {
+	input := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`)
+	status, output := runMain(t, input, "-mode=solve", "-expensive", "-stats")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	if !strings.Contains(output.String(), "algoDeathBlossom") {
+		t.Errorf("output does not contain stats for algoDeathBlossom")
+	}
+
+	b := NewBoard()
+	_, err := b.ReadFrom(output)
+	if err != nil {
+		t.Errorf("error reading output board: %s", err)
+	}
+	if !b.Solved() {
+		t.Errorf("output board is not solved")
+	}
+}
+
+func TestMainSolve_forcingChains(t *testing.T) {
+	input := `_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`
+	status, output := runMain(t, strings.NewReader(input), "-mode=solve", "-forcingChains", "-nishio", "-stats")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	for _, name := range []string{"algoForcingChains", "algoNishio"} {
+		if !strings.Contains(output.String(), name) {
+			t.Errorf("output does not contain stats for %s", name)
+		}
+	}
+	if strings.Contains(output.String(), "algoDeathBlossom") {
+		t.Errorf("output contains stats for algoDeathBlossom, expected only the forcing chains")
+	}
+
+	b := NewBoard()
+	_, err := b.ReadFrom(output)
+	if err != nil {
+		t.Errorf("error reading output board: %s", err)
+	}
+	if !b.Solved() {
+		t.Errorf("output board is not solved")
+	}
+}
+
+func TestMainClassify(t *testing.T) {
+	input := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ _ _ _ _ _
+1 1 _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+_ _ _ _ _ _ _ _ _
+`)
+	status, output := runMain(t, input, "-mode=classify")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	expectedOutput := "unique\nmultiple\nnone\n"
+	if output.String() != expectedOutput {
+		t.Errorf("output is %q, expected %q", output.String(), expectedOutput)
+	}
+}
+
+func TestMainEnumerate(t *testing.T) {
+	input := `1 8 7 3 6 9 4 5 2
+5 4 6 2 8 7 9 3 1
+9 3 2 1 5 4 8 6 7
+2 1 _ _ 3 8 7 4 6
+4 6 _ _ 7 2 3 1 8
+3 7 8 6 4 1 2 9 5
+7 5 4 8 9 6 1 2 3
+8 2 3 4 1 5 6 7 9
+6 9 1 7 2 3 5 8 4
+`
+	status, output := runMain(t, strings.NewReader(input), "-mode=enumerate")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	for i := 0; i < 2; i++ {
+		b := NewBoard()
+		_, err := b.ReadFrom(output)
+		if err != nil {
+			t.Errorf("error reading output board %d: %s", i, err)
+		}
+		if !b.Solved() {
+			t.Errorf("output board %d is not solved", i)
+		}
+	}
+	if output.Len() != 0 {
+		t.Errorf("output has %d extra bytes, expected none", output.Len())
+	}
+
+	status, output = runMain(t, strings.NewReader(input), "-mode=enumerate", "-limit=1")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	if output.Len() != 9*9*2 {
+		t.Errorf("output is %d bytes, expected %d", output.Len(), 9*9*2)
+	}
+
+	status, output = runMain(t, strings.NewReader(input), "-mode=enumerate", "-guessTile=mrvDegree", "-guessOrder=lcv")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	if output.Len() != 2*9*9*2 {
+		t.Errorf("output is %d bytes, expected %d", output.Len(), 2*9*9*2)
+	}
+
+	status, output = runMain(t, strings.NewReader(input), "-mode=enumerate", "-unique", "-expensive", "-nishio")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	if output.Len() != 2*9*9*2 {
+		t.Errorf("output is %d bytes, expected %d", output.Len(), 2*9*9*2)
+	}
+}
+
+func TestMainSolve_dlx(t *testing.T) {
+	input := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`)
+	status, output := runMain(t, input, "-mode=solve", "-engine=dlx", "-stats")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	if !strings.Contains(output.String(), "dlx") {
+		t.Errorf("output does not contain stats for dlx")
+	}
+
+	b := NewBoard()
+	_, err := b.ReadFrom(output)
+	if err != nil {
+		t.Errorf("error reading output board: %s", err)
+	}
+	if !b.Solved() {
+		t.Errorf("output board is not solved")
+	}
+}
+
+func TestMainSolve_invalidEngine(t *testing.T) {
+	status, _ := runMain(t, nil, "-mode=solve", "-engine=foo")
+	if status != 1 {
+		t.Errorf("main returned %d, expected %d", status, 1)
+	}
+}
+
+func TestMainSolve_guessHeuristics(t *testing.T) {
+	input := `_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`
+	status, output := runMain(t, strings.NewReader(input), "-mode=solve", "-guessTile=mrvDegree", "-guessOrder=lcv")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+
+	b := NewBoard()
+	_, err := b.ReadFrom(output)
+	if err != nil {
+		t.Errorf("error reading output board: %s", err)
+	}
+	if !b.Solved() {
+		t.Errorf("output board is not solved")
+	}
+
+	status, _ = runMain(t, nil, "-mode=solve", "-guessTile=foo")
+	if status != 1 {
+		t.Errorf("main returned %d, expected %d", status, 1)
+	}
+	status, _ = runMain(t, nil, "-mode=solve", "-guessOrder=foo")
+	if status != 1 {
+		t.Errorf("main returned %d, expected %d", status, 1)
+	}
+}
+
+func TestMainDIMACS(t *testing.T) {
+	input := strings.NewReader(`_ 8 _ _ 6 _ _ _ _
+5 4 _ _ _ 7 _ 3 _
+_ _ _ 1 _ _ 8 6 7
+_ _ 9 _ 3 _ _ _ 6
+_ _ 5 _ _ _ 3 _ _
+3 _ _ _ 4 _ 2 _ _
+7 5 4 _ _ 6 _ _ _
+_ 2 _ 4 _ _ _ 7 9
+_ _ _ _ 2 _ _ 8 _
+`)
+	status, output := runMain(t, input, "-mode=dimacs")
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	if !strings.Contains(output.String(), "\np cnf 729 ") {
+		t.Errorf("output does not contain the problem line")
+	}
+}
+
+func TestMainApplyModel(t *testing.T) {
+	solution := NewBoard()
+	solution.ReadFrom(strings.NewReader(dimacsTestSolution))
+	f, err := ioutil.TempFile("", "soodohkoo-model")
+	if err != nil {
+		t.Fatalf("error creating model file: %s", err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(dimacsTestModel(solution))
+	f.Close()
+
+	status, output := runMain(t, strings.NewReader(dimacsTestBoard), "-mode=applyModel", "-model="+f.Name())
+	if status != 0 {
+		t.Errorf("main returned %d, expected %d", status, 0)
+	}
+	if output.String() != dimacsTestSolution {
+		t.Errorf("output is\n%s\nexpected\n%s", output.String(), dimacsTestSolution)
+	}
+}